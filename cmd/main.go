@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"edumatch/internal/app/routers"
 	"edumatch/internal/config"
 	"edumatch/internal/dependencies"
@@ -30,6 +31,11 @@ func main() {
 		log.Println("Failed to initialize dependencies")
 		return
 	}
+	// Start background jobs
+	for _, job := range app.Jobs {
+		go job.Run(context.Background())
+	}
+
 	// Initialize Gin router
 	router := gin.Default()
 
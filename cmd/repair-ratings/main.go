@@ -0,0 +1,26 @@
+package main
+
+import (
+	"edumatch/internal/app/repositories"
+	"edumatch/internal/config"
+	database "edumatch/pkg/db"
+	"log"
+)
+
+// repair-ratings recomputes rating aggregates stored on edu centers and courses from ratings table
+func main() {
+	//Loading env
+	config.LoadEnv()
+
+	//Initialize DataBase
+	if err := database.InitDataBase(); err != nil {
+		log.Fatalln("Failed to connect to the database")
+	}
+
+	repaired, err := repositories.NewRatingRepository(database.GetDB()).RepairRatingAggregates()
+	if err != nil {
+		log.Fatalf("Failed to repair rating aggregates: %v", err)
+	}
+
+	log.Printf("Rating aggregates repaired: %d edu centers, %d courses", repaired.EduCenters, repaired.Courses)
+}
@@ -11,26 +11,58 @@ var CustomErrors = map[string]int{
 	// utils errors
 	ErrHandleBinding.Error(): http.StatusBadRequest,
 	ErrInvalidID.Error():     http.StatusBadRequest,
+	ErrInvalidCursor.Error(): http.StatusBadRequest,
 	// eduCenter errors
 	ErrEduCenterExist.Error():    http.StatusBadRequest,
 	ErrEduCenterNotFound.Error(): http.StatusNotFound,
+	// geocoding errors
+	ErrPlaceNotFound.Error(): http.StatusNotFound,
 	// users errors
 	ErrUserExist.Error():     http.StatusBadRequest,
 	ErrUserNotFound.Error():  http.StatusNotFound,
 	ErrWrongPassword.Error(): http.StatusBadRequest,
+	// email verification errors
+	ErrInvalidVerificationToken.Error(): http.StatusBadRequest,
+	ErrEmailNotSet.Error():              http.StatusBadRequest,
+	ErrEmailAlreadyVerified.Error():     http.StatusBadRequest,
+	ErrVerificationTooSoon.Error():      http.StatusTooManyRequests,
+	ErrEmailNotVerified.Error():         http.StatusForbidden,
 	// course errors
 	ErrCourseNotFound.Error(): http.StatusNotFound,
 	ErrCourseExists.Error():   http.StatusBadRequest,
-	ErrInvalidToken.Error():   http.StatusUnauthorized,
+	// schedule errors
+	ErrScheduleNotFound.Error(): http.StatusNotFound,
+	// discount errors
+	ErrDiscountNotFound.Error(): http.StatusNotFound,
+	// rating errors
+	ErrRatingNotFound.Error():   http.StatusNotFound,
+	ErrResponseNotFound.Error(): http.StatusNotFound,
+	// review errors
+	ErrReviewNotFound.Error(): http.StatusNotFound,
+	ErrVoteOwnReview.Error():  http.StatusBadRequest,
+	// moderation errors
+	ErrReportNotFound.Error():       http.StatusNotFound,
+	ErrReportTargetNotFound.Error(): http.StatusNotFound,
+	ErrReportExists.Error():         http.StatusBadRequest,
+	ErrReportResolved.Error():       http.StatusBadRequest,
+	// match errors
+	ErrStudentProfileNotFound.Error(): http.StatusNotFound,
 	//auth
-	ErrUnauthorized.Error():      http.StatusUnauthorized,
-	ErrUserNoLongerExist.Error(): http.StatusUnauthorized,
+	ErrInvalidToken.Error():       http.StatusUnauthorized,
+	ErrUnauthorized.Error():       http.StatusUnauthorized,
+	ErrUserNoLongerExist.Error():  http.StatusUnauthorized,
+	ErrForbidden.Error():          http.StatusForbidden,
+	ErrRefreshTokenReused.Error(): http.StatusUnauthorized,
+	ErrSessionTerminated.Error():  http.StatusUnauthorized,
+	ErrSessionNotFound.Error():    http.StatusNotFound,
+	ErrInvalidResetToken.Error():  http.StatusBadRequest,
 }
 
 // utils errors
 var (
 	ErrHandleBinding = errors.New("invalid request payload")
 	ErrInvalidID     = errors.New("invalid id provided")
+	ErrInvalidCursor = errors.New("invalid cursor provided")
 )
 
 // eduCenter errors
@@ -39,6 +71,9 @@ var (
 	ErrEduCenterNotFound = errors.New("education center not found")
 )
 
+// geocoding errors
+var ErrPlaceNotFound = errors.New("no known place found")
+
 // users errors
 var (
 	ErrUserExist            = errors.New("user with this username or email already exists")
@@ -47,17 +82,64 @@ var (
 	ErrCanNotGetUserFromCTX = errors.New("cannot get user from ctx")
 )
 
+// email verification errors
+var (
+	ErrInvalidVerificationToken = errors.New("email verification link is invalid or expired")
+	ErrEmailNotSet              = errors.New("user has no email to verify")
+	ErrEmailAlreadyVerified     = errors.New("email is already verified")
+	ErrVerificationTooSoon      = errors.New("verification link was sent recently, try again later")
+	ErrEmailNotVerified         = errors.New("verify your email to do this")
+)
+
 // course errors
 var (
 	ErrCourseNotFound = errors.New("course not found")
 	ErrCourseExists   = errors.New("course is exists")
 )
 
+// schedule errors
+var ErrScheduleNotFound = errors.New("schedule not found")
+
+// discount errors
+var ErrDiscountNotFound = errors.New("discount not found")
+
+// rating errors
+var (
+	ErrRatingNotFound   = errors.New("rating not found")
+	ErrResponseNotFound = errors.New("rating response not found")
+)
+
+// review errors
+var (
+	ErrReviewNotFound = errors.New("review not found")
+	ErrVoteOwnReview  = errors.New("you can not vote for your own review")
+)
+
+// moderation errors
+var (
+	ErrReportNotFound       = errors.New("report not found")
+	ErrReportTargetNotFound = errors.New("reported item not found")
+	ErrReportExists         = errors.New("you have already reported this item")
+	ErrReportResolved       = errors.New("report is already resolved")
+)
+
+// recommendation errors
+var ErrSimilaritiesRunning = errors.New("item similarities are already being computed")
+
+// match errors
+var ErrStudentProfileNotFound = errors.New("student profile not found, fill it to get matches")
+
 // auth errors
 var (
 	ErrInvalidToken      = errors.New("invalid token")
 	ErrUnauthorized      = errors.New("you are not allowed to this endpoint")
 	ErrUserNoLongerExist = errors.New("user belonging to this token no longer exist")
+	ErrForbidden         = errors.New("you are not allowed to modify this resource")
+	// rotated refresh token was presented again, so one of its copies is stolen
+	ErrRefreshTokenReused = errors.New("refresh token was already used, its session is terminated")
+	ErrSessionTerminated  = errors.New("session is terminated, log in again")
+	ErrSessionNotFound    = errors.New("session not found")
+	ErrInvalidResetToken  = errors.New("password reset token is invalid or expired")
 )
 
 // validation(not handles as usual errors)
@@ -7,6 +7,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
@@ -14,7 +15,15 @@ type AuthHandlerInterface interface {
 	SignUp(c *gin.Context)
 	Login(c *gin.Context)
 	ProtectedEndpoint(roles ...models.Role) gin.HandlerFunc
+	VerifiedEmailRequired() gin.HandlerFunc
 	RefreshToken(c *gin.Context)
+	Logout(c *gin.Context)
+	LogoutAll(c *gin.Context)
+	GetMySessions(c *gin.Context)
+	TerminateMySession(c *gin.Context)
+	ChangeMyPassword(c *gin.Context)
+	ForgotPassword(c *gin.Context)
+	ResetPassword(c *gin.Context)
 }
 
 type AuthHandler struct {
@@ -47,7 +56,7 @@ func (h *AuthHandler) SignUp(c *gin.Context) {
 		return
 	}
 	// register user
-	tokens, err := h.authService.RegisterUser(user)
+	tokens, err := h.authService.RegisterUser(user, GetClient(c))
 	if err != nil {
 		c.Error(err)
 		return
@@ -77,7 +86,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 	// Generate a new JWT token
-	tokens, err := h.authService.Login(loggingUser)
+	tokens, err := h.authService.Login(loggingUser, GetClient(c))
 	if err != nil {
 		c.Error(err)
 		return
@@ -95,19 +104,27 @@ func (h *AuthHandler) ProtectedEndpoint(roles ...models.Role) gin.HandlerFunc {
 		if token == "" {
 			token = c.Query("token")
 		}
-		userID, role, err := services.ValidateToken(token, false)
+		claims, err := services.ValidateToken(token)
 		if err != nil {
 			c.Error(err)
 			c.Abort()
 			return
 		}
+		userID, role := claims.UserID, claims.Role
 		if !h.authService.UserStillExists(userID) {
 			c.Error(custom_errors.ErrUserNoLongerExist)
 			c.Abort()
 			return
 		}
+		//token of terminated session is rejected before it expires
+		if err := h.authService.CheckSession(claims, GetClient(c)); err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
 		c.Set("user_id", userID)
 		c.Set("user_role", role)
+		c.Set("session_id", claims.SessionID)
 
 		if len(roles) > 0 {
 			// Check if the user has the required role
@@ -130,26 +147,238 @@ func (h *AuthHandler) ProtectedEndpoint(roles ...models.Role) gin.HandlerFunc {
 	}
 }
 
-// ! Izzat should give me info about this api
+// VerifiedEmailRequired keeps users without verified email out when it is configured, it goes after ProtectedEndpoint
+func (h *AuthHandler) VerifiedEmailRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := h.authService.CheckEmailVerified(GetRequester(c).UserID); err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// Refresh Token ...
+// @Summary Refresh Token
+// @Description This API for exchanging refresh token for new access and refresh tokens. Refresh token can be used once, using it again logs out its session
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body models.RefreshTokenDto false "Refresh token, it can be sent in Authorization header instead"
+// @Success 200 {object} models.Tokens
+// @Failure 401 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/auth/refresh [POST]
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
-	refreshToken := c.PostForm("refresh_token")
-	if refreshToken == "" {
-		refreshToken = c.GetHeader("Authorization")
+	tokens, err := h.authService.RefreshTokens(getRefreshToken(c), GetClient(c))
+	if err != nil {
+		c.Error(err)
+		return
 	}
 
-	// Validate the refresh token
-	userID, userRole, err := services.ValidateToken(refreshToken, true)
+	//logging
+	LoggingResponse(c, "RefreshToken", h.logger)
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout ...
+// @Summary Logout
+// @Description This API for logging out session of refresh token, access tokens issued in the session are rejected right away
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body models.RefreshTokenDto false "Refresh token, it can be sent in Authorization header instead"
+// @Success 200 {object} models.Empty
+// @Failure 401 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/auth/logout [POST]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	if err := h.authService.Logout(getRefreshToken(c)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "Logout", h.logger)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+// Logout All ...
+// @Summary Logout All
+// @Description This API for logging out current user on all devices, access tokens of all sessions are rejected right away
+// @Security BearerAuth
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Empty
+// @Failure 401 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/auth/logout-all [POST]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	if err := h.authService.LogoutAll(GetRequester(c).UserID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "LogoutAll", h.logger)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out on all devices successfully"})
+}
+
+// Get My Sessions ...
+// @Summary Get My Sessions
+// @Description This API for getting devices current user is logged in on
+// @Security BearerAuth
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Sessions
+// @Failure 401 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/users/me/sessions [GET]
+func (h *AuthHandler) GetMySessions(c *gin.Context) {
+	sessions, err := h.authService.GetSessions(GetRequester(c).UserID, c.MustGet("session_id").(uuid.UUID))
 	if err != nil {
 		c.Error(err)
 		return
 	}
 
-	// Generate a new JWT token
-	token, err := services.GenerateToken(userID, userRole, false)
+	//logging
+	LoggingResponse(c, "GetMySessions", h.logger)
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// Terminate My Session ...
+// @Summary Terminate My Session
+// @Description This API for logging out current user on a device, access tokens of the session stop working right away
+// @Security BearerAuth
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param id path string true "Session_id"
+// @Success 200 {object} models.Empty
+// @Failure 400 {object} models.CustomError
+// @Failure 401 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/users/me/sessions/{id} [DELETE]
+func (h *AuthHandler) TerminateMySession(c *gin.Context) {
+	sessionID, err := GetId(c, h.logger)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		c.Error(err)
+		return
+	}
+
+	if err := h.authService.TerminateSession(GetRequester(c).UserID, sessionID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "TerminateMySession", h.logger)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session terminated successfully"})
+}
+
+// Change My Password ...
+// @Summary Change My Password
+// @Description This API for changing password of current user, the old password is required. Other sessions of user are terminated
+// @Security BearerAuth
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body models.ChangePasswordDto true "Old and new passwords"
+// @Success 200 {object} models.Empty
+// @Failure 400 {object} models.CustomError
+// @Failure 401 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/users/me/password [PUT]
+func (h *AuthHandler) ChangeMyPassword(c *gin.Context) {
+	var dto models.ChangePasswordDto
+	if err := HandleJSONBinding(c, &dto, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authService.ChangePassword(GetRequester(c).UserID, c.MustGet("session_id").(uuid.UUID), dto); err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "ChangeMyPassword", h.logger)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully"})
+}
+
+// Forgot Password ...
+// @Summary Forgot Password
+// @Description This API for mailing link to reset forgotten password to verified email. Response is the same whether email is registered or not
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body models.ForgotPasswordDto true "Email of user"
+// @Success 200 {object} models.Empty
+// @Failure 400 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/auth/password/forgot [POST]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var dto models.ForgotPasswordDto
+	if err := HandleJSONBinding(c, &dto, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authService.ForgotPassword(dto); err != nil {
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"token": token})
+	//logging
+	LoggingResponse(c, "ForgotPassword", h.logger)
+
+	c.JSON(http.StatusOK, gin.H{"message": "If the email is registered, a link to reset password is sent to it"})
+}
+
+// Reset Password ...
+// @Summary Reset Password
+// @Description This API for setting new password by token of mailed link. Token works once and all sessions of user are terminated
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body models.ResetPasswordDto true "Token and new password"
+// @Success 200 {object} models.Empty
+// @Failure 400 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/auth/password/reset [POST]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var dto models.ResetPasswordDto
+	if err := HandleJSONBinding(c, &dto, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.authService.ResetPassword(dto); err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "ResetPassword", h.logger)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully, log in with the new password"})
+}
+
+// getRefreshToken reads refresh token from JSON or form body, falling back to Authorization header
+func getRefreshToken(c *gin.Context) string {
+	var body models.RefreshTokenDto
+	if err := c.ShouldBind(&body); err == nil && body.RefreshToken != "" {
+		return body.RefreshToken
+	}
+	return c.GetHeader("Authorization")
 }
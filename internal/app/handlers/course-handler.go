@@ -3,6 +3,7 @@ package handlers
 import (
 	"edumatch/internal/app/models"
 	"edumatch/internal/app/services"
+	"edumatch/pkg/ical"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -17,6 +18,15 @@ type CourseHandlerInterface interface {
 	GetAllCourses(c *gin.Context)
 	DeleteCourse(c *gin.Context)
 	GiveRating(c *gin.Context)
+	GetMyRating(c *gin.Context)
+	DeleteRating(c *gin.Context)
+	GetEduCenterCourses(c *gin.Context)
+	CreateEduCenterCourse(c *gin.Context)
+	GetCourseDiscounts(c *gin.Context)
+	CreateDiscount(c *gin.Context)
+	DeleteDiscount(c *gin.Context)
+	GetCourseCalendar(c *gin.Context)
+	GetEduCenterCalendar(c *gin.Context)
 }
 type CourseHandler struct {
 	courseService services.CourseServiceInterface
@@ -40,6 +50,7 @@ func NewCourseHandler(courseService services.CourseServiceInterface, logger *zap
 // @Param body body models.Course true "CourseBody"
 // @Success 200 {object} models.Course
 // @Failure 400 {object} models.CustomError
+// @Failure 403 {object} models.CustomError
 // @Failure 500 {object} models.CustomError
 // @Router /api/courses [POST]
 func (h *CourseHandler) CreateCourse(c *gin.Context) {
@@ -48,7 +59,7 @@ func (h *CourseHandler) CreateCourse(c *gin.Context) {
 		c.Error(err)
 		return
 	}
-	createdUser, err := h.courseService.CreateCourse(course)
+	createdUser, err := h.courseService.CreateCourse(course, GetRequester(c))
 	if err != nil {
 		c.Error(err)
 		return
@@ -70,6 +81,7 @@ func (h *CourseHandler) CreateCourse(c *gin.Context) {
 // @Param body body models.Course true "CourseBody"
 // @Success 200 {object} models.Course
 // @Failure 400 {object} models.CustomError
+// @Failure 403 {object} models.CustomError
 // @Failure 500 {object} models.CustomError
 // @Router /api/courses [PUT]
 func (h *CourseHandler) UpdateCourse(c *gin.Context) {
@@ -79,7 +91,7 @@ func (h *CourseHandler) UpdateCourse(c *gin.Context) {
 		return
 	}
 
-	course, err := h.courseService.UpdateCourse(newCourse)
+	course, err := h.courseService.UpdateCourse(newCourse, GetRequester(c))
 	if err != nil {
 		c.Error(err)
 		return
@@ -124,12 +136,35 @@ func (h *CourseHandler) GetCourse(c *gin.Context) {
 // @Tags Course
 // @Accept json
 // @Produce json
+// @Param page query int false "Page number, ignored when cursor is given"
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor from next_cursor of previous page"
+// @Param sort_by query string false "Sort key, rating sorts by weighted rating and price by discounted price" Enums(rating, created_at, price)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Param edu_center_id query string false "EduCenter_ID"
+// @Param teacher query string false "Teacher name"
+// @Param min_rating query number false "Minimum average rating"
+// @Param max_rating query number false "Maximum rating"
+// @Param q query string false "Text in name or description"
+// @Param day query int false "Day of week of lessons, 0 is Sunday"
+// @Param time_from query string false "Lessons start not earlier than (15:04)"
+// @Param time_to query string false "Lessons end not later than (15:04)"
+// @Param currency query string false "ISO 4217 currency of prices, required by price filters and price sort"
+// @Param price_unit query string false "Unit of price, required by price filters and price sort" Enums(month, lesson, course)
+// @Param min_price query number false "Minimum discounted price"
+// @Param max_price query number false "Maximum discounted price"
 // @Success 200 {object} models.AllCourses
 // @Failure 400 {object} models.CustomError
 // @Failure 500 {object} models.CustomError
 // @Router /api/courses [GET]
 func (h *CourseHandler) GetAllCourses(c *gin.Context) {
-	courses, err := h.courseService.GetAllCourses()
+	var query models.CourseQuery
+	if err := HandleQueryBinding(c, &query, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+
+	courses, err := h.courseService.GetAllCourses(query)
 	if err != nil {
 		c.Error(err)
 		return
@@ -150,6 +185,7 @@ func (h *CourseHandler) GetAllCourses(c *gin.Context) {
 // @Param id path string true "Course_id"
 // @Success 200 {object} models.Empty
 // @Failure 400 {object} models.CustomError
+// @Failure 403 {object} models.CustomError
 // @Failure 500 {object} models.CustomError
 // @Router /api/courses/{id} [DELETE]
 func (h *CourseHandler) DeleteCourse(c *gin.Context) {
@@ -159,7 +195,7 @@ func (h *CourseHandler) DeleteCourse(c *gin.Context) {
 		return
 	}
 
-	if err := h.courseService.DeleteCourse(courseID); err != nil {
+	if err := h.courseService.DeleteCourse(courseID, GetRequester(c)); err != nil {
 		c.Error(err)
 		return
 	}
@@ -172,13 +208,15 @@ func (h *CourseHandler) DeleteCourse(c *gin.Context) {
 
 // Create Course Rating ...
 // @Summary Create Course Rating
-// @Description This API for creating course rating
+// @Description This API for creating course rating, repeated rating changes the score
+// @Security BearerAuth
 // @Tags Course
 // @Accept json
 // @Produce json
 // @Param body body models.CourseRating true "Create_Course_Rating"
 // @Success 200 {object} models.CourseRating
 // @Failure 400 {object} models.CustomError
+// @Failure 403 {object} models.CustomError
 // @Failure 500 {object} models.CustomError
 // @Router /api/courses/rating [POST]
 func (h *CourseHandler) GiveRating(c *gin.Context) {
@@ -192,12 +230,331 @@ func (h *CourseHandler) GiveRating(c *gin.Context) {
 	userID := c.MustGet("user_id").(uuid.UUID)
 	newCourseRating.OwnerID = userID
 
-	if err := h.courseService.GiveRating(newCourseRating); err != nil {
+	savedRating, err := h.courseService.GiveRating(newCourseRating)
+	if err != nil {
 		c.Error(err)
 		return
 	}
 
 	LoggingResponse(c, "GiveRating", h.logger)
 
-	c.JSON(http.StatusAccepted, gin.H{"message": "Rating accepted"})
+	c.JSON(http.StatusOK, savedRating)
+}
+
+// GetMyRating ...
+// @Summary Get My Course Rating
+// @Description This API for getting rating of current user for course
+// @Security BearerAuth
+// @Tags Course
+// @Accept json
+// @Produce json
+// @Param id path string true "Course_id"
+// @Success 200 {object} models.CourseRating
+// @Failure 400 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/courses/{id}/rating [GET]
+func (h *CourseHandler) GetMyRating(c *gin.Context) {
+	courseID, err := GetId(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	rating, err := h.courseService.GetUserRating(courseID, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	LoggingResponse(c, "GetMyRating", h.logger)
+
+	c.JSON(http.StatusOK, rating)
+}
+
+// DeleteRating ...
+// @Summary Delete Course Rating
+// @Description This API for retracting rating of current user for course
+// @Security BearerAuth
+// @Tags Course
+// @Accept json
+// @Produce json
+// @Param id path string true "Course_id"
+// @Success 200 {object} models.Empty
+// @Failure 400 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/courses/{id}/rating [DELETE]
+func (h *CourseHandler) DeleteRating(c *gin.Context) {
+	courseID, err := GetId(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.courseService.DeleteRating(courseID, userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	LoggingResponse(c, "DeleteRating", h.logger)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Rating deleted successfully"})
+}
+
+// GetEduCenterCourses ...
+// @Summary GetEduCenterCourses
+// @Description This API for getting courses of EduCenter
+// @Tags Course
+// @Accept json
+// @Produce json
+// @Param id path string true "EduCenter_ID"
+// @Param page query int false "Page number, ignored when cursor is given"
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor from next_cursor of previous page"
+// @Param sort_by query string false "Sort key, rating sorts by weighted rating and price by discounted price" Enums(rating, created_at, price)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Param teacher query string false "Teacher name"
+// @Param min_rating query number false "Minimum average rating"
+// @Param max_rating query number false "Maximum rating"
+// @Param q query string false "Text in name or description"
+// @Param day query int false "Day of week of lessons, 0 is Sunday"
+// @Param time_from query string false "Lessons start not earlier than (15:04)"
+// @Param time_to query string false "Lessons end not later than (15:04)"
+// @Param currency query string false "ISO 4217 currency of prices, required by price filters and price sort"
+// @Param price_unit query string false "Unit of price, required by price filters and price sort" Enums(month, lesson, course)
+// @Param min_price query number false "Minimum discounted price"
+// @Param max_price query number false "Maximum discounted price"
+// @Success 200 {object} models.AllCourses
+// @Failure 400 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/educenters/{id}/courses [GET]
+func (h *CourseHandler) GetEduCenterCourses(c *gin.Context) {
+	eduCenterID, err := GetId(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	var query models.CourseQuery
+	if err := HandleQueryBinding(c, &query, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+
+	courses, err := h.courseService.GetEduCenterCourses(eduCenterID, query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	LoggingResponse(c, "GetEduCenterCourses", h.logger)
+
+	c.JSON(http.StatusOK, courses)
+}
+
+// CreateEduCenterCourse ...
+// @Summary CreateEduCenterCourse
+// @Description This API for creating course of EduCenter
+// @Security BearerAuth
+// @Tags Course
+// @Accept json
+// @Produce json
+// @Param id path string true "EduCenter_ID"
+// @Param body body models.CreateCourseDto true "CourseBody"
+// @Success 201 {object} models.Course
+// @Failure 400 {object} models.CustomError
+// @Failure 403 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/educenters/{id}/courses [POST]
+func (h *CourseHandler) CreateEduCenterCourse(c *gin.Context) {
+	eduCenterID, err := GetId(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	var course models.CreateCourseDto
+	if err := HandleJSONBinding(c, &course, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+
+	createdCourse, err := h.courseService.CreateEduCenterCourse(eduCenterID, course, GetRequester(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	LoggingResponse(c, "CreateEduCenterCourse", h.logger)
+
+	c.JSON(http.StatusCreated, createdCourse)
+}
+
+// GetCourseDiscounts ...
+// @Summary Get Course Discounts
+// @Description This API for getting running and upcoming discount campaigns of course
+// @Tags Course
+// @Accept json
+// @Produce json
+// @Param id path string true "Course_id"
+// @Success 200 {object} models.CourseDiscounts
+// @Failure 400 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/courses/{id}/discounts [GET]
+func (h *CourseHandler) GetCourseDiscounts(c *gin.Context) {
+	courseID, err := GetId(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	discounts, err := h.courseService.GetCourseDiscounts(courseID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	LoggingResponse(c, "GetCourseDiscounts", h.logger)
+
+	c.JSON(http.StatusOK, discounts)
+}
+
+// CreateDiscount ...
+// @Summary Create Course Discount
+// @Description This API for creating discount campaign of course in percents, the biggest running discount is applied to price
+// @Security BearerAuth
+// @Tags Course
+// @Accept json
+// @Produce json
+// @Param id path string true "Course_id"
+// @Param body body models.CourseDiscountDto true "DiscountBody"
+// @Success 201 {object} models.CourseDiscount
+// @Failure 400 {object} models.CustomError
+// @Failure 403 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/courses/{id}/discounts [POST]
+func (h *CourseHandler) CreateDiscount(c *gin.Context) {
+	courseID, err := GetId(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	var discount models.CourseDiscountDto
+	if err := HandleJSONBinding(c, &discount, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+	discount.CourseID = courseID
+
+	createdDiscount, err := h.courseService.CreateDiscount(discount, GetRequester(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	LoggingResponse(c, "CreateDiscount", h.logger)
+
+	c.JSON(http.StatusCreated, createdDiscount)
+}
+
+// DeleteDiscount ...
+// @Summary Delete Course Discount
+// @Description This API for deleting discount campaign of course
+// @Security BearerAuth
+// @Tags Course
+// @Accept json
+// @Produce json
+// @Param id path string true "Course_id"
+// @Param discount_id path string true "Discount_id"
+// @Success 200 {object} models.Empty
+// @Failure 400 {object} models.CustomError
+// @Failure 403 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/courses/{id}/discounts/{discount_id} [DELETE]
+func (h *CourseHandler) DeleteDiscount(c *gin.Context) {
+	courseID, err := GetId(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	discountID, err := GetIdParam(c, "discount_id", h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.courseService.DeleteDiscount(courseID, discountID, GetRequester(c)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	LoggingResponse(c, "DeleteDiscount", h.logger)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Discount deleted successfully"})
+}
+
+// GetCourseCalendar ...
+// @Summary Get Course Calendar
+// @Description This API for getting iCalendar feed of weekly lessons of all groups of course
+// @Tags Course
+// @Produce text/calendar
+// @Param id path string true "Course_id"
+// @Success 200 {string} string "iCalendar feed"
+// @Failure 400 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/courses/{id}/calendar.ics [GET]
+func (h *CourseHandler) GetCourseCalendar(c *gin.Context) {
+	courseID, err := GetId(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	calendar, err := h.courseService.GetCourseCalendar(courseID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	LoggingResponse(c, "GetCourseCalendar", h.logger)
+
+	c.Header("Content-Disposition", `inline; filename="course.ics"`)
+	c.Data(http.StatusOK, ical.ContentType, calendar)
+}
+
+// GetEduCenterCalendar ...
+// @Summary Get EduCenter Calendar
+// @Description This API for getting iCalendar feed of weekly lessons of all courses of EduCenter
+// @Tags Course
+// @Produce text/calendar
+// @Param id path string true "EduCenter_ID"
+// @Success 200 {string} string "iCalendar feed"
+// @Failure 400 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/educenters/{id}/calendar.ics [GET]
+func (h *CourseHandler) GetEduCenterCalendar(c *gin.Context) {
+	eduCenterID, err := GetId(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	calendar, err := h.courseService.GetEduCenterCalendar(eduCenterID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	LoggingResponse(c, "GetEduCenterCalendar", h.logger)
+
+	c.Header("Content-Disposition", `inline; filename="educenter.ics"`)
+	c.Data(http.StatusOK, ical.ContentType, calendar)
 }
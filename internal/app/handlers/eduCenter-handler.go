@@ -18,6 +18,9 @@ type EduCenterHandlerInterface interface {
 	DeleteEduCenter(c *gin.Context)
 	GiveRating(c *gin.Context)
 	GetEduCenterByLocation(c *gin.Context)
+	GetEduCenterMap(c *gin.Context)
+	GetMyRating(c *gin.Context)
+	DeleteRating(c *gin.Context)
 }
 type EduCenterHandler struct {
 	eduCenterService services.EduCenterServiceInterface
@@ -37,12 +40,31 @@ func NewEduCenterHandler(eduCenterService services.EduCenterServiceInterface, lo
 // @Tags EduCenter
 // @Accept json
 // @Produce json
+// @Param page query int false "Page number, ignored when cursor is given"
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor from next_cursor of previous page"
+// @Param sort_by query string false "Sort key, rating sorts by weighted rating" Enums(rating, name, created_at, distance)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Param min_rating query number false "Minimum average rating"
+// @Param has_contacts query bool false "Only centers with (or without) contacts"
+// @Param name_prefix query string false "Name prefix"
+// @Param created_after query string false "Created after (RFC3339)"
+// @Param latitude query number false "Latitude, required for distance sort"
+// @Param longitude query number false "Longitude, required for distance sort"
+// @Param format query string false "Response format, geojson is also selected by Accept: application/geo+json" Enums(json, geojson)
+// @Produce application/geo+json
 // @Success 200 {object} models.AllEduCenters
 // @Failure 400 {object} models.CustomError
 // @Failure 500 {object} models.CustomError
 // @Router /api/educenters [GET]
 func (h *EduCenterHandler) GetAllEduCenters(c *gin.Context) {
-	eduCenters, err := h.eduCenterService.GetAllEduCenters()
+	var query models.EduCenterQuery
+	if err := HandleQueryBinding(c, &query, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+
+	eduCenters, err := h.eduCenterService.GetAllEduCenters(query)
 
 	if err != nil {
 		c.Error(err)
@@ -52,6 +74,11 @@ func (h *EduCenterHandler) GetAllEduCenters(c *gin.Context) {
 	//logging
 	LoggingResponse(c, "GetAllEduCenters", h.logger)
 
+	if WantsGeoJSON(c) {
+		c.Header("Content-Type", models.GeoJSONContentType)
+		c.JSON(http.StatusOK, models.NewEduCenterFeatureCollection(eduCenters))
+		return
+	}
 	c.JSON(http.StatusOK, eduCenters)
 }
 
@@ -65,6 +92,7 @@ func (h *EduCenterHandler) GetAllEduCenters(c *gin.Context) {
 // @Param body body models.EduCenter true "CourseBody"
 // @Success 200 {object} models.EduCenter
 // @Failure 400 {object} models.CustomError
+// @Failure 403 {object} models.CustomError
 // @Failure 500 {object} models.CustomError
 // @Router /api/educenters [POST]
 func (h *EduCenterHandler) CreateEduCenter(c *gin.Context) {
@@ -97,6 +125,7 @@ func (h *EduCenterHandler) CreateEduCenter(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path string true "EduCenter_ID"
+// @Param include_courses query bool false "Embed course summary"
 // @Success 200 {object} models.EduCenter
 // @Failure 400 {object} models.CustomError
 // @Failure 500 {object} models.CustomError
@@ -107,8 +136,9 @@ func (h *EduCenterHandler) GetEduCenter(c *gin.Context) {
 		c.Error(err)
 		return
 	}
+	includeCourses := c.Query("include_courses") == "true"
 
-	eduCenter, err := h.eduCenterService.GetEduCenter(eduCenterID)
+	eduCenter, err := h.eduCenterService.GetEduCenter(eduCenterID, includeCourses)
 
 	if err != nil {
 		c.Error(err)
@@ -132,6 +162,7 @@ func (h *EduCenterHandler) GetEduCenter(c *gin.Context) {
 //	@Param body body models.EduCenter true "EduCenter"
 //	@Success 200 {object} models.EduCenter
 //	@Failure 400 {object} models.CustomError
+//	@Failure 403 {object} models.CustomError
 //	@Failure 500 {object} models.CustomError
 //	@Router /api/educenters [PATCH]
 func (h *EduCenterHandler) UpdateEduCenter(c *gin.Context) {
@@ -148,7 +179,7 @@ func (h *EduCenterHandler) UpdateEduCenter(c *gin.Context) {
 	}
 	eduCenter.ID = eduCenterID
 
-	updatedEduCenter, err := h.eduCenterService.UpdateEduCenter(eduCenter)
+	updatedEduCenter, err := h.eduCenterService.UpdateEduCenter(eduCenter, GetRequester(c))
 	if err != nil {
 		c.Error(err)
 		return
@@ -170,6 +201,7 @@ func (h *EduCenterHandler) UpdateEduCenter(c *gin.Context) {
 // @Param id path string true "EduCenter_ID"
 // @Success 200 {object} models.Empty
 // @Failure 400 {object} models.CustomError
+// @Failure 403 {object} models.CustomError
 // @Failure 500 {object} models.CustomError
 // @Router /api/educenters [DELETE]
 func (h *EduCenterHandler) DeleteEduCenter(c *gin.Context) {
@@ -178,7 +210,7 @@ func (h *EduCenterHandler) DeleteEduCenter(c *gin.Context) {
 		c.Error(err)
 		return
 	}
-	if err := h.eduCenterService.DeleteEduCenter(eduCenterID); err != nil {
+	if err := h.eduCenterService.DeleteEduCenter(eduCenterID, GetRequester(c)); err != nil {
 		c.Error(err)
 		return
 	}
@@ -189,6 +221,20 @@ func (h *EduCenterHandler) DeleteEduCenter(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Education Center deleted successfully"})
 }
 
+// Give EduCenter Rating ...
+// @Summary Give EduCenter Rating
+// @Description This API for rating EduCenter, repeated rating changes the score
+// @Security BearerAuth
+// @Tags EduCenter
+// @Accept json
+// @Produce json
+// @Param body body models.EduCenterRating true "EduCenter_Rating"
+// @Success 200 {object} models.EduCenterRating
+// @Failure 400 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 403 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/educenters/rating [POST]
 func (h *EduCenterHandler) GiveRating(c *gin.Context) {
 	var rating models.EduCenterRating
 	if err := HandleJSONBinding(c, &rating, h.logger); err != nil {
@@ -200,13 +246,79 @@ func (h *EduCenterHandler) GiveRating(c *gin.Context) {
 	userID := c.MustGet("user_id").(uuid.UUID)
 	rating.OwnerID = userID
 
-	if err := h.eduCenterService.GiveRating(rating); err != nil {
+	savedRating, err := h.eduCenterService.GiveRating(rating)
+	if err != nil {
 		c.Error(err)
 		return
 	}
 	//logging
 	LoggingResponse(c, "GiveRating", h.logger)
-	c.JSON(http.StatusOK, gin.H{"message": "Rating accepted"})
+	c.JSON(http.StatusOK, savedRating)
+}
+
+// Get My EduCenter Rating ...
+// @Summary Get My EduCenter Rating
+// @Description This API for getting rating of current user for EduCenter
+// @Security BearerAuth
+// @Tags EduCenter
+// @Accept json
+// @Produce json
+// @Param id path string true "EduCenter_ID"
+// @Success 200 {object} models.EduCenterRating
+// @Failure 400 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/educenters/{id}/rating [GET]
+func (h *EduCenterHandler) GetMyRating(c *gin.Context) {
+	eduCenterID, err := GetId(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	rating, err := h.eduCenterService.GetUserRating(eduCenterID, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "GetMyRating", h.logger)
+
+	c.JSON(http.StatusOK, rating)
+}
+
+// Delete EduCenter Rating ...
+// @Summary Delete EduCenter Rating
+// @Description This API for retracting rating of current user for EduCenter
+// @Security BearerAuth
+// @Tags EduCenter
+// @Accept json
+// @Produce json
+// @Param id path string true "EduCenter_ID"
+// @Success 200 {object} models.Empty
+// @Failure 400 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/educenters/{id}/rating [DELETE]
+func (h *EduCenterHandler) DeleteRating(c *gin.Context) {
+	eduCenterID, err := GetId(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.eduCenterService.DeleteRating(eduCenterID, userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "DeleteRating", h.logger)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Rating deleted successfully"})
 }
 
 // GetEduCenterByLocation(location models.NearEduCenterDto) (models.AllNearEduCenters, error)
@@ -217,6 +329,8 @@ func (h *EduCenterHandler) GiveRating(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param body body models.NearEduCenterDto true "EduCenter_Location"
+// @Param format query string false "Response format, geojson is also selected by Accept: application/geo+json" Enums(json, geojson)
+// @Produce application/geo+json
 // @Success 200 {object} models.AllNearEduCenters
 // @Failure 400 {object} models.CustomError
 // @Failure 500 {object} models.CustomError
@@ -237,5 +351,44 @@ func (h *EduCenterHandler) GetEduCenterByLocation(c *gin.Context) {
 
 	LoggingResponse(c, "GetEduCenterByLocation", h.logger)
 
+	if WantsGeoJSON(c) {
+		c.Header("Content-Type", models.GeoJSONContentType)
+		c.JSON(http.StatusAccepted, models.NewNearEduCenterFeatureCollection(eduCenters))
+		return
+	}
 	c.JSON(http.StatusAccepted, eduCenters)
 }
+
+// Get EduCenter Map
+// @Summary Get EduCenter Map
+// @Description This API for getting educenters inside map viewport, close centers are grouped into clusters depending on zoom
+// @Tags EduCenter
+// @Accept json
+// @Produce json
+// @Param bbox query string true "Viewport as min_longitude,min_latitude,max_longitude,max_latitude"
+// @Param zoom query int true "Map zoom level (0-22)"
+// @Param min_rating query number false "Minimum average rating"
+// @Param has_contacts query bool false "Only centers with (or without) contacts"
+// @Param name_prefix query string false "Name prefix"
+// @Param created_after query string false "Created after (RFC3339)"
+// @Success 200 {object} models.EduCenterMap
+// @Failure 400 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/educenters/map [GET]
+func (h *EduCenterHandler) GetEduCenterMap(c *gin.Context) {
+	var query models.EduCenterMapQuery
+	if err := HandleQueryBinding(c, &query, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+
+	eduCenterMap, err := h.eduCenterService.GetEduCenterMap(query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	LoggingResponse(c, "GetEduCenterMap", h.logger)
+
+	c.JSON(http.StatusOK, eduCenterMap)
+}
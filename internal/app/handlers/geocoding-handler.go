@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"edumatch/internal/app/models"
+	"edumatch/internal/app/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type GeocodingHandlerInterface interface {
+	ReverseGeocode(c *gin.Context)
+}
+
+type GeocodingHandler struct {
+	geocodingService services.GeocodingServiceInterface
+	logger           *zap.Logger
+}
+
+func NewGeocodingHandler(geocodingService services.GeocodingServiceInterface, logger *zap.Logger) GeocodingHandlerInterface {
+	return &GeocodingHandler{
+		geocodingService: geocodingService,
+		logger:           logger,
+	}
+}
+
+// Reverse Geocode ...
+// @Summary Reverse Geocode
+// @Description This API for getting address of the nearest known street, district or city around point
+// @Tags Geocoding
+// @Accept json
+// @Produce json
+// @Param latitude query number true "Latitude"
+// @Param longitude query number true "Longitude"
+// @Success 200 {object} models.GeocodedAddress
+// @Failure 400 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/geocode/reverse [GET]
+func (h *GeocodingHandler) ReverseGeocode(c *gin.Context) {
+	var query models.ReverseGeocodeQuery
+	if err := HandleQueryBinding(c, &query, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+
+	address, err := h.geocodingService.ReverseGeocode(query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "ReverseGeocode", h.logger)
+
+	c.JSON(http.StatusOK, address)
+}
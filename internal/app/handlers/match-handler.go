@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"edumatch/internal/app/models"
+	"edumatch/internal/app/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type MatchHandlerInterface interface {
+	GetMyProfile(c *gin.Context)
+	SaveMyProfile(c *gin.Context)
+	Match(c *gin.Context)
+}
+
+type MatchHandler struct {
+	matchService services.MatchServiceInterface
+	logger       *zap.Logger
+}
+
+func NewMatchHandler(matchService services.MatchServiceInterface, logger *zap.Logger) MatchHandlerInterface {
+	return &MatchHandler{
+		matchService: matchService,
+		logger:       logger,
+	}
+}
+
+// Get My Profile ...
+// @Summary Get My Student Profile
+// @Description This API for getting preferences of current user courses are matched by
+// @Security BearerAuth
+// @Tags Match
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.StudentProfile
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/users/me/profile [GET]
+func (h *MatchHandler) GetMyProfile(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	profile, err := h.matchService.GetStudentProfile(userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "GetMyProfile", h.logger)
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// Save My Profile ...
+// @Summary Save My Student Profile
+// @Description This API for creating or replacing preferences of current user courses are matched by
+// @Security BearerAuth
+// @Tags Match
+// @Accept json
+// @Produce json
+// @Param body body models.StudentProfileDto true "Student_Profile"
+// @Success 200 {object} models.StudentProfile
+// @Failure 400 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/users/me/profile [PUT]
+func (h *MatchHandler) SaveMyProfile(c *gin.Context) {
+	var profile models.StudentProfileDto
+	if err := HandleJSONBinding(c, &profile, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+	profile.UserID = c.MustGet("user_id").(uuid.UUID)
+
+	savedProfile, err := h.matchService.SaveStudentProfile(profile)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "SaveMyProfile", h.logger)
+
+	c.JSON(http.StatusOK, savedProfile)
+}
+
+// Match ...
+// @Summary Match Courses And Edu Centers
+// @Description This API for getting courses and edu centers ranked by how well they match profile of current user, with score of each factor
+// @Security BearerAuth
+// @Tags Match
+// @Accept json
+// @Produce json
+// @Param type query string false "Type of matches" Enums(edu_center, course)
+// @Param page query int false "Page number, ignored when cursor is given"
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor from next_cursor of previous page"
+// @Param order query string false "Order of score" Enums(asc, desc)
+// @Success 200 {object} models.Matches
+// @Failure 400 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/match [GET]
+func (h *MatchHandler) Match(c *gin.Context) {
+	var query models.MatchQuery
+	if err := HandleQueryBinding(c, &query, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	matches, err := h.matchService.Match(userID, query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "Match", h.logger)
+
+	c.JSON(http.StatusOK, matches)
+}
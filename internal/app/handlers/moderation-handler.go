@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"edumatch/internal/app/models"
+	"edumatch/internal/app/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type ModerationHandlerInterface interface {
+	ReportItem(c *gin.Context)
+	GetReports(c *gin.Context)
+	DecideReport(c *gin.Context)
+}
+
+type ModerationHandler struct {
+	moderationService services.ModerationServiceInterface
+	logger            *zap.Logger
+}
+
+func NewModerationHandler(moderationService services.ModerationServiceInterface, logger *zap.Logger) ModerationHandlerInterface {
+	return &ModerationHandler{
+		moderationService: moderationService,
+		logger:            logger,
+	}
+}
+
+// Report Item ...
+// @Summary Report Item
+// @Description This API for reporting EduCenter, course or rating
+// @Security BearerAuth
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Param body body models.CreateReportDto true "Report"
+// @Success 201 {object} models.Report
+// @Failure 400 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/reports [POST]
+func (h *ModerationHandler) ReportItem(c *gin.Context) {
+	var report models.CreateReportDto
+	if err := HandleJSONBinding(c, &report, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+	//attaching reporter
+	report.ReporterID = c.MustGet("user_id").(uuid.UUID)
+
+	createdReport, err := h.moderationService.ReportItem(report)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "ReportItem", h.logger)
+
+	c.JSON(http.StatusCreated, createdReport)
+}
+
+// Get Reports ...
+// @Summary Get Reports
+// @Description This API for getting moderation queue
+// @Security BearerAuth
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Param status query string false "Report status" Enums(pending, resolved)
+// @Param target_type query string false "Reported item type" Enums(edu_center, course, rating)
+// @Param page query int false "Page number, ignored when cursor is given"
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor from next_cursor of previous page"
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Success 200 {object} models.AllReports
+// @Failure 400 {object} models.CustomError
+// @Failure 401 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/moderation/reports [GET]
+func (h *ModerationHandler) GetReports(c *gin.Context) {
+	var query models.ReportQuery
+	if err := HandleQueryBinding(c, &query, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+
+	reports, err := h.moderationService.GetReports(query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "GetReports", h.logger)
+
+	c.JSON(http.StatusOK, reports)
+}
+
+// Decide Report ...
+// @Summary Decide Report
+// @Description This API for hiding, restoring or deleting reported item
+// @Security BearerAuth
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Param id path string true "Report_ID"
+// @Param body body models.ModerationDecisionDto true "Decision"
+// @Success 200 {object} models.Report
+// @Failure 400 {object} models.CustomError
+// @Failure 401 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/moderation/reports/{id}/decision [POST]
+func (h *ModerationHandler) DecideReport(c *gin.Context) {
+	reportID, err := GetId(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	var decision models.ModerationDecisionDto
+	if err := HandleJSONBinding(c, &decision, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+	//attaching moderator
+	decision.ModeratorID = c.MustGet("user_id").(uuid.UUID)
+
+	report, err := h.moderationService.DecideReport(reportID, decision)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "DecideReport", h.logger)
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"edumatch/internal/app/models"
+	"edumatch/internal/app/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type RatingResponseHandlerInterface interface {
+	SaveResponse(c *gin.Context)
+	DeleteResponse(c *gin.Context)
+	GetEduCenterRatings(c *gin.Context)
+	GetUnansweredRatings(c *gin.Context)
+}
+
+type RatingResponseHandler struct {
+	ratingResponseService services.RatingResponseServiceInterface
+	logger                *zap.Logger
+}
+
+func NewRatingResponseHandler(ratingResponseService services.RatingResponseServiceInterface, logger *zap.Logger) RatingResponseHandlerInterface {
+	return &RatingResponseHandler{
+		ratingResponseService: ratingResponseService,
+		logger:                logger,
+	}
+}
+
+// Save Response ...
+// @Summary Save Response
+// @Description This API for responding to rating of own EduCenter or its course, existing response is replaced
+// @Security BearerAuth
+// @Tags RatingResponse
+// @Accept json
+// @Produce json
+// @Param id path string true "Rating_ID"
+// @Param body body models.RatingResponseDto true "Response"
+// @Success 200 {object} models.RatingResponse
+// @Failure 400 {object} models.CustomError
+// @Failure 403 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/ratings/{id}/response [PUT]
+func (h *RatingResponseHandler) SaveResponse(c *gin.Context) {
+	ratingID, err := GetId(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	var response models.RatingResponseDto
+	if err := HandleJSONBinding(c, &response, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+	response.OwnerID = c.MustGet("user_id").(uuid.UUID)
+
+	savedResponse, err := h.ratingResponseService.SaveResponse(ratingID, response)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "SaveResponse", h.logger)
+
+	c.JSON(http.StatusOK, savedResponse)
+}
+
+// Delete Response ...
+// @Summary Delete Response
+// @Description This API for deleting response to rating
+// @Security BearerAuth
+// @Tags RatingResponse
+// @Accept json
+// @Produce json
+// @Param id path string true "Rating_ID"
+// @Success 200 {object} models.Empty
+// @Failure 400 {object} models.CustomError
+// @Failure 403 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/ratings/{id}/response [DELETE]
+func (h *RatingResponseHandler) DeleteResponse(c *gin.Context) {
+	ratingID, err := GetId(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.ratingResponseService.DeleteResponse(ratingID, userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "DeleteResponse", h.logger)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Response deleted successfully"})
+}
+
+// Get EduCenter Ratings ...
+// @Summary Get EduCenter Ratings
+// @Description This API for getting ratings of EduCenter and its courses together with owner responses
+// @Tags RatingResponse
+// @Accept json
+// @Produce json
+// @Param id path string true "EduCenter_ID"
+// @Param page query int false "Page number, ignored when cursor is given"
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor from next_cursor of previous page"
+// @Param order query string false "Sort order by creation time" Enums(asc, desc)
+// @Success 200 {object} models.AllRatingsWithResponses
+// @Failure 400 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/educenters/{id}/ratings [GET]
+func (h *RatingResponseHandler) GetEduCenterRatings(c *gin.Context) {
+	eduCenterID, err := GetId(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	var query models.PageQuery
+	if err := HandleQueryBinding(c, &query, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+
+	ratings, err := h.ratingResponseService.GetEduCenterRatings(eduCenterID, query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "GetEduCenterRatings", h.logger)
+
+	c.JSON(http.StatusOK, ratings)
+}
+
+// Get Unanswered Ratings ...
+// @Summary Get Unanswered Ratings
+// @Description This API for getting low ratings without response of current user's EduCenters and their courses
+// @Security BearerAuth
+// @Tags RatingResponse
+// @Accept json
+// @Produce json
+// @Param max_score query int false "Highest score considered low (default 2)"
+// @Param page query int false "Page number, ignored when cursor is given"
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor from next_cursor of previous page"
+// @Param order query string false "Sort order by creation time" Enums(asc, desc)
+// @Success 200 {object} models.AllRatingsWithResponses
+// @Failure 400 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/ratings/unanswered [GET]
+func (h *RatingResponseHandler) GetUnansweredRatings(c *gin.Context) {
+	var query models.UnansweredRatingsQuery
+	if err := HandleQueryBinding(c, &query, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	ratings, err := h.ratingResponseService.GetUnansweredRatings(userID, query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "GetUnansweredRatings", h.logger)
+
+	c.JSON(http.StatusOK, ratings)
+}
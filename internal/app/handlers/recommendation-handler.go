@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"edumatch/internal/app/models"
+	"edumatch/internal/app/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type RecommendationHandlerInterface interface {
+	GetSimilarCourses(c *gin.Context)
+	GetMyRecommendations(c *gin.Context)
+}
+
+type RecommendationHandler struct {
+	recommendationService services.RecommendationServiceInterface
+	logger                *zap.Logger
+}
+
+func NewRecommendationHandler(recommendationService services.RecommendationServiceInterface, logger *zap.Logger) RecommendationHandlerInterface {
+	return &RecommendationHandler{
+		recommendationService: recommendationService,
+		logger:                logger,
+	}
+}
+
+// Get Similar Courses ...
+// @Summary Get Similar Courses
+// @Description This API for getting courses rated alike by the same students
+// @Tags Recommendation
+// @Accept json
+// @Produce json
+// @Param id path string true "Course_id"
+// @Param limit query int false "Number of courses (max 50)"
+// @Success 200 {object} models.Recommendations
+// @Failure 400 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/courses/{id}/similar [GET]
+func (h *RecommendationHandler) GetSimilarCourses(c *gin.Context) {
+	courseID, err := GetId(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	var query models.SimilarQuery
+	if err := HandleQueryBinding(c, &query, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+
+	courses, err := h.recommendationService.GetSimilarCourses(courseID, query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "GetSimilarCourses", h.logger)
+
+	c.JSON(http.StatusOK, courses)
+}
+
+// Get My Recommendations ...
+// @Summary Get My Recommendations
+// @Description This API for getting edu centers and courses liked by students who rated alike, popular ones nearby for users without ratings
+// @Security BearerAuth
+// @Tags Recommendation
+// @Accept json
+// @Produce json
+// @Param latitude query number false "Latitude of area for users without ratings"
+// @Param longitude query number false "Longitude of area for users without ratings"
+// @Param limit query int false "Number of recommendations (max 50)"
+// @Success 200 {object} models.Recommendations
+// @Failure 400 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/users/me/recommendations [GET]
+func (h *RecommendationHandler) GetMyRecommendations(c *gin.Context) {
+	var query models.RecommendationQuery
+	if err := HandleQueryBinding(c, &query, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	recommendations, err := h.recommendationService.GetUserRecommendations(userID, query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "GetMyRecommendations", h.logger)
+
+	c.JSON(http.StatusOK, recommendations)
+}
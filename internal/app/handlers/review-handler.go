@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"edumatch/internal/app/models"
+	"edumatch/internal/app/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type ReviewHandlerInterface interface {
+	GetEduCenterReviews(c *gin.Context)
+	GetCourseReviews(c *gin.Context)
+	SaveEduCenterReview(c *gin.Context)
+	SaveCourseReview(c *gin.Context)
+	VoteHelpful(c *gin.Context)
+	DeleteHelpfulVote(c *gin.Context)
+}
+
+type ReviewHandler struct {
+	reviewService services.ReviewServiceInterface
+	logger        *zap.Logger
+}
+
+func NewReviewHandler(reviewService services.ReviewServiceInterface, logger *zap.Logger) ReviewHandlerInterface {
+	return &ReviewHandler{
+		reviewService: reviewService,
+		logger:        logger,
+	}
+}
+
+// Get EduCenter Reviews ...
+// @Summary Get EduCenter Reviews
+// @Description This API for getting reviews of EduCenter
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param id path string true "EduCenter_ID"
+// @Param page query int false "Page number, ignored when cursor is given"
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor from next_cursor of previous page"
+// @Param sort_by query string false "Sort key" Enums(newest, helpful)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Success 200 {object} models.AllReviews
+// @Failure 400 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/educenters/{id}/reviews [GET]
+func (h *ReviewHandler) GetEduCenterReviews(c *gin.Context) {
+	h.getReviews(c, models.EduCenterTarget, "GetEduCenterReviews")
+}
+
+// Get Course Reviews ...
+// @Summary Get Course Reviews
+// @Description This API for getting reviews of course
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param id path string true "Course_id"
+// @Param page query int false "Page number, ignored when cursor is given"
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor from next_cursor of previous page"
+// @Param sort_by query string false "Sort key" Enums(newest, helpful)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Success 200 {object} models.AllReviews
+// @Failure 400 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/courses/{id}/reviews [GET]
+func (h *ReviewHandler) GetCourseReviews(c *gin.Context) {
+	h.getReviews(c, models.CourseTarget, "GetCourseReviews")
+}
+
+// Save EduCenter Review ...
+// @Summary Save EduCenter Review
+// @Description This API for writing or editing review of current user's rating of EduCenter
+// @Security BearerAuth
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param id path string true "EduCenter_ID"
+// @Param body body models.ReviewDto true "Review"
+// @Success 200 {object} models.Review
+// @Failure 400 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/educenters/{id}/review [PUT]
+func (h *ReviewHandler) SaveEduCenterReview(c *gin.Context) {
+	h.saveReview(c, models.EduCenterTarget, "SaveEduCenterReview")
+}
+
+// Save Course Review ...
+// @Summary Save Course Review
+// @Description This API for writing or editing review of current user's rating of course
+// @Security BearerAuth
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param id path string true "Course_id"
+// @Param body body models.ReviewDto true "Review"
+// @Success 200 {object} models.Review
+// @Failure 400 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/courses/{id}/review [PUT]
+func (h *ReviewHandler) SaveCourseReview(c *gin.Context) {
+	h.saveReview(c, models.CourseTarget, "SaveCourseReview")
+}
+
+// Vote Helpful ...
+// @Summary Vote Helpful
+// @Description This API for marking review as helpful
+// @Security BearerAuth
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param id path string true "Review_ID"
+// @Success 200 {object} models.Empty
+// @Failure 400 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/reviews/{id}/helpful [POST]
+func (h *ReviewHandler) VoteHelpful(c *gin.Context) {
+	reviewID, err := GetId(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.reviewService.VoteHelpful(reviewID, userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "VoteHelpful", h.logger)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Vote accepted"})
+}
+
+// Delete Helpful Vote ...
+// @Summary Delete Helpful Vote
+// @Description This API for removing helpful mark from review
+// @Security BearerAuth
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param id path string true "Review_ID"
+// @Success 200 {object} models.Empty
+// @Failure 400 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/reviews/{id}/helpful [DELETE]
+func (h *ReviewHandler) DeleteHelpfulVote(c *gin.Context) {
+	reviewID, err := GetId(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.reviewService.DeleteHelpfulVote(reviewID, userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "DeleteHelpfulVote", h.logger)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Vote deleted successfully"})
+}
+
+func (h *ReviewHandler) getReviews(c *gin.Context, target models.RatingTarget, info string) {
+	targetID, err := GetId(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	var query models.ReviewQuery
+	if err := HandleQueryBinding(c, &query, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+
+	reviews, err := h.reviewService.GetReviews(target, targetID, query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, info, h.logger)
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+func (h *ReviewHandler) saveReview(c *gin.Context, target models.RatingTarget, info string) {
+	targetID, err := GetId(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	var review models.ReviewDto
+	if err := HandleJSONBinding(c, &review, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	savedReview, err := h.reviewService.SaveReview(target, targetID, userID, review)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, info, h.logger)
+
+	c.JSON(http.StatusOK, savedReview)
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"edumatch/internal/app/models"
+	"edumatch/internal/app/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ScheduleHandlerInterface interface {
+	GetCourseSchedules(c *gin.Context)
+	CreateSchedule(c *gin.Context)
+	UpdateSchedule(c *gin.Context)
+	DeleteSchedule(c *gin.Context)
+}
+
+type ScheduleHandler struct {
+	scheduleService services.ScheduleServiceInterface
+	logger          *zap.Logger
+}
+
+func NewScheduleHandler(scheduleService services.ScheduleServiceInterface, logger *zap.Logger) ScheduleHandlerInterface {
+	return &ScheduleHandler{
+		scheduleService: scheduleService,
+		logger:          logger,
+	}
+}
+
+// Get Course Schedules ...
+// @Summary Get Course Schedules
+// @Description This API for getting groups of course with their weekly slots
+// @Tags Schedule
+// @Accept json
+// @Produce json
+// @Param id path string true "Course_id"
+// @Success 200 {object} models.CourseSchedules
+// @Failure 400 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/courses/{id}/schedules [GET]
+func (h *ScheduleHandler) GetCourseSchedules(c *gin.Context) {
+	courseID, err := GetId(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	schedules, err := h.scheduleService.GetCourseSchedules(courseID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "GetCourseSchedules", h.logger)
+
+	c.JSON(http.StatusOK, schedules)
+}
+
+// Create Schedule ...
+// @Summary Create Schedule
+// @Description This API for creating group of course, slots can't overlap other groups of center in the same room or with the same teacher
+// @Security BearerAuth
+// @Tags Schedule
+// @Accept json
+// @Produce json
+// @Param id path string true "Course_id"
+// @Param body body models.CourseScheduleDto true "ScheduleBody"
+// @Success 201 {object} models.CourseSchedule
+// @Failure 400 {object} models.CustomError
+// @Failure 403 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/courses/{id}/schedules [POST]
+func (h *ScheduleHandler) CreateSchedule(c *gin.Context) {
+	courseID, err := GetId(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	var schedule models.CourseScheduleDto
+	if err := HandleJSONBinding(c, &schedule, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+	schedule.CourseID = courseID
+
+	createdSchedule, err := h.scheduleService.CreateSchedule(schedule, GetRequester(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "CreateSchedule", h.logger)
+
+	c.JSON(http.StatusCreated, createdSchedule)
+}
+
+// Update Schedule ...
+// @Summary Update Schedule
+// @Description This API for updating group of course, its slots are replaced by given ones. Calendar events follow slots by their order, so edited slot keeps its place
+// @Security BearerAuth
+// @Tags Schedule
+// @Accept json
+// @Produce json
+// @Param id path string true "Course_id"
+// @Param schedule_id path string true "Schedule_id"
+// @Param body body models.CourseScheduleDto true "ScheduleBody"
+// @Success 200 {object} models.CourseSchedule
+// @Failure 400 {object} models.CustomError
+// @Failure 403 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/courses/{id}/schedules/{schedule_id} [PUT]
+func (h *ScheduleHandler) UpdateSchedule(c *gin.Context) {
+	courseID, err := GetId(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	scheduleID, err := GetIdParam(c, "schedule_id", h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	var schedule models.CourseScheduleDto
+	if err := HandleJSONBinding(c, &schedule, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+	schedule.ID = scheduleID
+	schedule.CourseID = courseID
+
+	updatedSchedule, err := h.scheduleService.UpdateSchedule(schedule, GetRequester(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "UpdateSchedule", h.logger)
+
+	c.JSON(http.StatusOK, updatedSchedule)
+}
+
+// Delete Schedule ...
+// @Summary Delete Schedule
+// @Description This API for deleting group of course
+// @Security BearerAuth
+// @Tags Schedule
+// @Accept json
+// @Produce json
+// @Param id path string true "Course_id"
+// @Param schedule_id path string true "Schedule_id"
+// @Success 200 {object} models.Empty
+// @Failure 400 {object} models.CustomError
+// @Failure 403 {object} models.CustomError
+// @Failure 404 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/courses/{id}/schedules/{schedule_id} [DELETE]
+func (h *ScheduleHandler) DeleteSchedule(c *gin.Context) {
+	courseID, err := GetId(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	scheduleID, err := GetIdParam(c, "schedule_id", h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.scheduleService.DeleteSchedule(courseID, scheduleID, GetRequester(c)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "DeleteSchedule", h.logger)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Schedule deleted successfully"})
+}
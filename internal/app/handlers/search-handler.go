@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"edumatch/internal/app/models"
+	"edumatch/internal/app/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type SearchHandlerInterface interface {
+	Search(c *gin.Context)
+	Suggest(c *gin.Context)
+}
+
+type SearchHandler struct {
+	searchService services.SearchServiceInterface
+	logger        *zap.Logger
+}
+
+func NewSearchHandler(searchService services.SearchServiceInterface, logger *zap.Logger) SearchHandlerInterface {
+	return &SearchHandler{
+		searchService: searchService,
+		logger:        logger,
+	}
+}
+
+// Search ...
+// @Summary Search
+// @Description This API for searching edu centers and courses by name, address, description and teacher. Misspelled text is matched by similarity
+// @Tags Search
+// @Accept json
+// @Produce json
+// @Param q query string true "Search text"
+// @Param type query string false "Type of results" Enums(edu_center, course)
+// @Param min_rating query number false "Minimum rating (0-5)"
+// @Param latitude query number false "Latitude, required for distance"
+// @Param longitude query number false "Longitude, required for distance"
+// @Param distance query number false "Radius in km around latitude and longitude"
+// @Param page query int false "Page number, ignored when cursor is given"
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor from next_cursor of previous page"
+// @Param order query string false "Order of rank" Enums(asc, desc)
+// @Success 200 {object} models.SearchResults
+// @Failure 400 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/search [GET]
+func (h *SearchHandler) Search(c *gin.Context) {
+	var query models.SearchQuery
+	if err := HandleQueryBinding(c, &query, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+
+	results, err := h.searchService.Search(query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "Search", h.logger)
+
+	c.JSON(http.StatusOK, results)
+}
+
+// Suggest ...
+// @Summary Suggest
+// @Description This API for completing search box prefix with names of edu centers, courses, teachers and districts
+// @Tags Search
+// @Accept json
+// @Produce json
+// @Param prefix query string true "Typed prefix"
+// @Param limit query int false "Number of suggestions of each type (max 10)"
+// @Success 200 {object} models.Suggestions
+// @Failure 400 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/suggest [GET]
+func (h *SearchHandler) Suggest(c *gin.Context) {
+	var query models.SuggestQuery
+	if err := HandleQueryBinding(c, &query, h.logger); err != nil {
+		c.Error(err)
+		return
+	}
+
+	suggestions, err := h.searchService.Suggest(query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "Suggest", h.logger)
+
+	//the same prefixes are typed over and over, let clients reuse them for a while
+	c.Header("Cache-Control", "public, max-age=60")
+	c.JSON(http.StatusOK, suggestions)
+}
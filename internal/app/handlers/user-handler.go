@@ -14,6 +14,8 @@ type UserHandlerInterface interface {
 	GetUser(c *gin.Context)
 	UpdateUser(c *gin.Context)
 	DeleteUser(c *gin.Context)
+	SendVerificationEmail(c *gin.Context)
+	VerifyEmail(c *gin.Context)
 	// CreateUser(c *gin.Context)
 }
 type UserHandler struct {
@@ -96,6 +98,7 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 // @Param body body models.User true "User_body"
 // @Success 200 {object} models.User
 // @Failure 400 {object} models.CustomError
+// @Failure 403 {object} models.CustomError
 // @Failure 500 {object} models.CustomError
 // @Router /api/users/{id} [PATCH]
 func (h *UserHandler) UpdateUser(c *gin.Context) {
@@ -112,7 +115,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	}
 	user.ID = userID
 
-	updatedUser, err := h.userService.UpdateUser(user)
+	updatedUser, err := h.userService.UpdateUser(user, GetRequester(c))
 
 	if err != nil {
 		c.Error(err)
@@ -135,6 +138,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 // @Param id path string true "User_ID"
 // @Success 200 {object} models.Empty
 // @Failure 400 {object} models.CustomError
+// @Failure 403 {object} models.CustomError
 // @Failure 500 {object} models.CustomError
 // @Router /api/users/{id} [DELETE]
 func (h *UserHandler) DeleteUser(c *gin.Context) {
@@ -144,7 +148,7 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		return
 	}
 
-	if err := h.userService.DeleteUser(userID); err != nil {
+	if err := h.userService.DeleteUser(userID, GetRequester(c)); err != nil {
 		c.Error(err)
 		return
 	}
@@ -155,6 +159,54 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
 }
 
+// Send Verification Email ...
+// @Summary Send Verification Email
+// @Description This API for mailing link verifying email of current user again, it can't be sent more often than once in resend interval
+// @Security BearerAuth
+// @Tags user
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Empty
+// @Failure 400 {object} models.CustomError
+// @Failure 401 {object} models.CustomError
+// @Failure 429 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/users/me/email/verification [POST]
+func (h *UserHandler) SendVerificationEmail(c *gin.Context) {
+	if err := h.userService.SendVerificationEmail(GetRequester(c).UserID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "SendVerificationEmail", h.logger)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Verification link sent successfully"})
+}
+
+// Verify Email ...
+// @Summary Verify Email
+// @Description This API for verifying email by token of mailed link, link of email user has changed since doesn't work
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param token query string true "Token of verification link"
+// @Success 200 {object} models.Empty
+// @Failure 400 {object} models.CustomError
+// @Failure 500 {object} models.CustomError
+// @Router /api/auth/verify-email [GET]
+func (h *UserHandler) VerifyEmail(c *gin.Context) {
+	if err := h.userService.VerifyEmail(c.Query("token")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	//logging
+	LoggingResponse(c, "VerifyEmail", h.logger)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified successfully"})
+}
+
 //NOT USED HANDLERS
 
 // func (h *UserHandler) CreateUser(c *gin.Context) {
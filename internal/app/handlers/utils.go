@@ -2,7 +2,9 @@ package handlers
 
 import (
 	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -10,7 +12,12 @@ import (
 )
 
 func GetId(c *gin.Context, logger *zap.Logger) (uuid.UUID, error) {
-	id := c.Param("id")
+	return GetIdParam(c, "id", logger)
+}
+
+// GetIdParam parses uuid from path parameter with given name
+func GetIdParam(c *gin.Context, name string, logger *zap.Logger) (uuid.UUID, error) {
+	id := c.Param(name)
 	ID, err := uuid.Parse(id)
 
 	if err != nil {
@@ -25,6 +32,26 @@ func GetId(c *gin.Context, logger *zap.Logger) (uuid.UUID, error) {
 	return ID, nil
 }
 
+// GetRequester returns user attached to context by ProtectedEndpoint
+func GetRequester(c *gin.Context) models.Requester {
+	return models.Requester{
+		UserID: c.MustGet("user_id").(uuid.UUID),
+		Role:   c.MustGet("user_role").(models.Role),
+	}
+}
+
+// maximum length of device description kept with tokens
+const maxDeviceLength = 255
+
+// GetClient describes device of request by its user agent together with its address
+func GetClient(c *gin.Context) models.Client {
+	device := []rune(c.Request.UserAgent())
+	if len(device) > maxDeviceLength {
+		device = device[:maxDeviceLength]
+	}
+	return models.Client{Device: string(device), IP: c.ClientIP()}
+}
+
 func HandleJSONBinding(c *gin.Context, target interface{}, logger *zap.Logger) error {
 	if err := c.ShouldBindJSON(&target); err != nil {
 		//logging
@@ -51,6 +78,19 @@ func HandleFormDataBinding(c *gin.Context, target interface{}, logger *zap.Logge
 	return nil
 }
 
+func HandleQueryBinding(c *gin.Context, target interface{}, logger *zap.Logger) error {
+	if err := c.ShouldBindQuery(target); err != nil {
+		//logging
+		logger.Error(custom_errors.ErrHandleBinding.Error(),
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status", http.StatusBadRequest),
+			zap.Error(err))
+		return custom_errors.ErrHandleBinding
+	}
+	return nil
+}
+
 func LoggingResponse(c *gin.Context, info string, logger *zap.Logger) {
 	logger.Info(info,
 		zap.String("method", c.Request.Method),
@@ -58,3 +98,11 @@ func LoggingResponse(c *gin.Context, info string, logger *zap.Logger) {
 		zap.Int("status", http.StatusOK),
 	)
 }
+
+// WantsGeoJSON reports whether client asked for GeoJSON by format query or Accept header
+func WantsGeoJSON(c *gin.Context) bool {
+	if format := c.Query("format"); format != "" {
+		return format == "geojson"
+	}
+	return strings.Contains(c.GetHeader("Accept"), models.GeoJSONContentType)
+}
@@ -0,0 +1,64 @@
+package jobs
+
+import (
+	"context"
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/services"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// JobInterface is the work application runs in background
+type JobInterface interface {
+	Run(ctx context.Context)
+}
+
+type SimilarityJob struct {
+	recommendationService services.RecommendationServiceInterface
+	interval              time.Duration
+	logger                *zap.Logger
+}
+
+func NewSimilarityJob(recommendationService services.RecommendationServiceInterface, interval time.Duration, logger *zap.Logger) JobInterface {
+	return &SimilarityJob{
+		recommendationService: recommendationService,
+		interval:              interval,
+		logger:                logger,
+	}
+}
+
+// Run recomputes item similarities right away and then every interval till ctx is done
+func (j *SimilarityJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.computeSimilarities()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *SimilarityJob) computeSimilarities() {
+	started := time.Now()
+	computed, err := j.recommendationService.ComputeSimilarities()
+	if errors.Is(err, custom_errors.ErrSimilaritiesRunning) {
+		j.logger.Info("Item similarities skipped, another instance is computing them")
+		return
+	}
+	if err != nil {
+		j.logger.Error("Failed to compute item similarities", zap.Error(err))
+		return
+	}
+	j.logger.Info("Item similarities computed",
+		zap.Int64("items", computed.Items),
+		zap.Int64("similarities", computed.Similarities),
+		zap.Duration("duration", time.Since(started)),
+	)
+}
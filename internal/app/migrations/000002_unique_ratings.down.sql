@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS "ratings_owner_course_key";
+DROP INDEX IF EXISTS "ratings_owner_edu_center_key";
+
+ALTER TABLE "ratings"
+    DROP COLUMN IF EXISTS "updated_at",
+    DROP COLUMN IF EXISTS "created_at";
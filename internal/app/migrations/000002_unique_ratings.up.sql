@@ -0,0 +1,13 @@
+-- keep only one rating of each user for the same center or course
+DELETE FROM "ratings" r
+USING "ratings" d
+WHERE r.owner_id = d.owner_id
+    AND (r.edu_center_id = d.edu_center_id OR r.course_id = d.course_id)
+    AND r.ctid < d.ctid;
+
+ALTER TABLE "ratings"
+    ADD COLUMN "created_at" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN "updated_at" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP;
+
+CREATE UNIQUE INDEX "ratings_owner_edu_center_key" ON "ratings" ("owner_id", "edu_center_id") WHERE "edu_center_id" IS NOT NULL;
+CREATE UNIQUE INDEX "ratings_owner_course_key" ON "ratings" ("owner_id", "course_id") WHERE "course_id" IS NOT NULL;
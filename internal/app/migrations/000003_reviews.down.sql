@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "ratings_course_id_idx";
+DROP INDEX IF EXISTS "ratings_edu_center_id_idx";
+
+DROP TABLE IF EXISTS "review_votes";
+DROP TABLE IF EXISTS "reviews";
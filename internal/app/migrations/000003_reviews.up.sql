@@ -0,0 +1,20 @@
+CREATE TABLE "reviews" (
+    "id" uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "rating_id" uuid UNIQUE NOT NULL REFERENCES "ratings" ("id") ON DELETE CASCADE,
+    "title" varchar(255) NOT NULL DEFAULT '',
+    "body" text NOT NULL DEFAULT '',
+    "pros" text NOT NULL DEFAULT '',
+    "cons" text NOT NULL DEFAULT '',
+    "created_at" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE "review_votes" (
+    "review_id" uuid REFERENCES "reviews" ("id") ON DELETE CASCADE,
+    "user_id" uuid REFERENCES "users" ("id"),
+    "created_at" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("review_id", "user_id")
+);
+
+CREATE INDEX "ratings_edu_center_id_idx" ON "ratings" ("edu_center_id");
+CREATE INDEX "ratings_course_id_idx" ON "ratings" ("course_id");
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "reports";
+
+ALTER TABLE "ratings" DROP COLUMN IF EXISTS "hidden_at";
+ALTER TABLE "courses" DROP COLUMN IF EXISTS "hidden_at";
+ALTER TABLE "edu_centers" DROP COLUMN IF EXISTS "hidden_at";
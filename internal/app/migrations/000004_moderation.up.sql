@@ -0,0 +1,22 @@
+ALTER TABLE "edu_centers" ADD COLUMN "hidden_at" TIMESTAMP WITH TIME ZONE;
+ALTER TABLE "courses" ADD COLUMN "hidden_at" TIMESTAMP WITH TIME ZONE;
+ALTER TABLE "ratings" ADD COLUMN "hidden_at" TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE "reports" (
+    "id" uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "reporter_id" uuid NOT NULL REFERENCES "users" ("id"),
+    "target_type" varchar(50) NOT NULL,
+    "target_id" uuid NOT NULL,
+    "reason" text NOT NULL,
+    "status" varchar(50) NOT NULL DEFAULT 'pending',
+    "decision" varchar(50),
+    "decision_note" text,
+    "decided_by" uuid REFERENCES "users" ("id"),
+    "decided_at" TIMESTAMP WITH TIME ZONE,
+    "created_at" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK ("target_type" IN ('edu_center', 'course', 'rating'))
+);
+
+-- one pending report of user for the same item
+CREATE UNIQUE INDEX "reports_pending_reporter_target_key" ON "reports" ("reporter_id", "target_type", "target_id") WHERE "status" = 'pending';
+CREATE INDEX "reports_status_created_at_idx" ON "reports" ("status", "created_at");
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS "edu_centers_owner_id_idx";
+DROP INDEX IF EXISTS "courses_edu_center_id_idx";
+
+DROP TABLE IF EXISTS "rating_responses";
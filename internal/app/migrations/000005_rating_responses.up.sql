@@ -0,0 +1,11 @@
+CREATE TABLE "rating_responses" (
+    "id" uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "rating_id" uuid UNIQUE NOT NULL REFERENCES "ratings" ("id") ON DELETE CASCADE,
+    "owner_id" uuid NOT NULL REFERENCES "users" ("id"),
+    "body" text NOT NULL,
+    "created_at" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "courses_edu_center_id_idx" ON "courses" ("edu_center_id");
+CREATE INDEX "edu_centers_owner_id_idx" ON "edu_centers" ("owner_id");
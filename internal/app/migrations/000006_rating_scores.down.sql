@@ -0,0 +1 @@
+ALTER TABLE "ratings" DROP CONSTRAINT IF EXISTS "ratings_score_range";
@@ -0,0 +1,3 @@
+-- scores are given in stars from 1 to 5. Scores given before, 0 was valid then, are kept as they are,
+-- so the check isn't validated against existing rows and applies to new and changed ones only.
+ALTER TABLE "ratings" ADD CONSTRAINT "ratings_score_range" CHECK ("score" BETWEEN 1 AND 5) NOT VALID;
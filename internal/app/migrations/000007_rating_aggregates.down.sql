@@ -0,0 +1,19 @@
+ALTER TABLE "edu_centers"
+    DROP COLUMN IF EXISTS "rating_sum",
+    DROP COLUMN IF EXISTS "rating_count",
+    DROP COLUMN IF EXISTS "rating_avg",
+    DROP COLUMN IF EXISTS "score_1",
+    DROP COLUMN IF EXISTS "score_2",
+    DROP COLUMN IF EXISTS "score_3",
+    DROP COLUMN IF EXISTS "score_4",
+    DROP COLUMN IF EXISTS "score_5";
+
+ALTER TABLE "courses"
+    DROP COLUMN IF EXISTS "rating_sum",
+    DROP COLUMN IF EXISTS "rating_count",
+    DROP COLUMN IF EXISTS "rating_avg",
+    DROP COLUMN IF EXISTS "score_1",
+    DROP COLUMN IF EXISTS "score_2",
+    DROP COLUMN IF EXISTS "score_3",
+    DROP COLUMN IF EXISTS "score_4",
+    DROP COLUMN IF EXISTS "score_5";
@@ -0,0 +1,52 @@
+-- aggregates of visible ratings, kept in sync by the application on every rating change
+ALTER TABLE "edu_centers"
+    ADD COLUMN "rating_sum" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN "rating_count" int NOT NULL DEFAULT 0,
+    ADD COLUMN "rating_avg" numeric(2, 1) NOT NULL DEFAULT 0,
+    ADD COLUMN "score_1" int NOT NULL DEFAULT 0,
+    ADD COLUMN "score_2" int NOT NULL DEFAULT 0,
+    ADD COLUMN "score_3" int NOT NULL DEFAULT 0,
+    ADD COLUMN "score_4" int NOT NULL DEFAULT 0,
+    ADD COLUMN "score_5" int NOT NULL DEFAULT 0;
+
+ALTER TABLE "courses"
+    ADD COLUMN "rating_sum" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN "rating_count" int NOT NULL DEFAULT 0,
+    ADD COLUMN "rating_avg" numeric(2, 1) NOT NULL DEFAULT 0,
+    ADD COLUMN "score_1" int NOT NULL DEFAULT 0,
+    ADD COLUMN "score_2" int NOT NULL DEFAULT 0,
+    ADD COLUMN "score_3" int NOT NULL DEFAULT 0,
+    ADD COLUMN "score_4" int NOT NULL DEFAULT 0,
+    ADD COLUMN "score_5" int NOT NULL DEFAULT 0;
+
+UPDATE "edu_centers" e
+SET "rating_sum" = a.sum, "rating_count" = a.count, "rating_avg" = a.avg,
+    "score_1" = a.score_1, "score_2" = a.score_2, "score_3" = a.score_3, "score_4" = a.score_4, "score_5" = a.score_5
+FROM (
+    SELECT edu_center_id AS id, SUM(score) AS sum, COUNT(*) AS count, ROUND(AVG(score), 1) AS avg,
+        COUNT(*) FILTER (WHERE score = 1) AS score_1,
+        COUNT(*) FILTER (WHERE score = 2) AS score_2,
+        COUNT(*) FILTER (WHERE score = 3) AS score_3,
+        COUNT(*) FILTER (WHERE score = 4) AS score_4,
+        COUNT(*) FILTER (WHERE score = 5) AS score_5
+    FROM "ratings"
+    WHERE edu_center_id IS NOT NULL AND hidden_at IS NULL
+    GROUP BY edu_center_id
+) a
+WHERE e.id = a.id;
+
+UPDATE "courses" c
+SET "rating_sum" = a.sum, "rating_count" = a.count, "rating_avg" = a.avg,
+    "score_1" = a.score_1, "score_2" = a.score_2, "score_3" = a.score_3, "score_4" = a.score_4, "score_5" = a.score_5
+FROM (
+    SELECT course_id AS id, SUM(score) AS sum, COUNT(*) AS count, ROUND(AVG(score), 1) AS avg,
+        COUNT(*) FILTER (WHERE score = 1) AS score_1,
+        COUNT(*) FILTER (WHERE score = 2) AS score_2,
+        COUNT(*) FILTER (WHERE score = 3) AS score_3,
+        COUNT(*) FILTER (WHERE score = 4) AS score_4,
+        COUNT(*) FILTER (WHERE score = 5) AS score_5
+    FROM "ratings"
+    WHERE course_id IS NOT NULL AND hidden_at IS NULL
+    GROUP BY course_id
+) a
+WHERE c.id = a.id;
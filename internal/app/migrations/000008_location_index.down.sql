@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS "edu_centers_location_earth_idx";
+
+DROP EXTENSION IF EXISTS earthdistance;
+DROP EXTENSION IF EXISTS cube;
@@ -0,0 +1,6 @@
+CREATE EXTENSION IF NOT EXISTS cube;
+CREATE EXTENSION IF NOT EXISTS earthdistance;
+
+-- location is stored as POINT(latitude, longitude)
+CREATE INDEX "edu_centers_location_earth_idx" ON "edu_centers" USING gist (ll_to_earth(location[0], location[1]))
+WHERE "deleted_at" IS NULL AND "hidden_at" IS NULL AND "location" IS NOT NULL;
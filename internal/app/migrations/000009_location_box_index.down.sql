@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "edu_centers_location_idx";
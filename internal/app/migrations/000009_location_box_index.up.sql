@@ -0,0 +1,3 @@
+-- viewport search of map looks up centers by location inside box
+CREATE INDEX "edu_centers_location_idx" ON "edu_centers" USING gist ("location")
+WHERE "deleted_at" IS NULL AND "hidden_at" IS NULL;
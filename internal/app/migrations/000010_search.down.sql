@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS "courses_teacher_trgm_idx";
+DROP INDEX IF EXISTS "courses_name_trgm_idx";
+DROP INDEX IF EXISTS "edu_centers_address_trgm_idx";
+DROP INDEX IF EXISTS "edu_centers_name_trgm_idx";
+DROP INDEX IF EXISTS "courses_search_vector_idx";
+DROP INDEX IF EXISTS "edu_centers_search_vector_idx";
+
+ALTER TABLE "courses" DROP COLUMN IF EXISTS "search_vector";
+ALTER TABLE "edu_centers" DROP COLUMN IF EXISTS "search_vector";
+
+DROP EXTENSION IF EXISTS pg_trgm;
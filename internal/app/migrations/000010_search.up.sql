@@ -0,0 +1,23 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- names are weighted above addresses and teachers, descriptions are indexed without html tags
+ALTER TABLE "edu_centers" ADD COLUMN "search_vector" tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', COALESCE("name", '')), 'A') ||
+    setweight(to_tsvector('simple', COALESCE("address", '')), 'B') ||
+    setweight(to_tsvector('simple', regexp_replace(COALESCE("html_description", ''), '<[^>]*>', ' ', 'g')), 'C')
+) STORED;
+
+ALTER TABLE "courses" ADD COLUMN "search_vector" tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', COALESCE("name", '')), 'A') ||
+    setweight(to_tsvector('simple', COALESCE("teacher", '')), 'B') ||
+    setweight(to_tsvector('simple', COALESCE("description", '')), 'C')
+) STORED;
+
+CREATE INDEX "edu_centers_search_vector_idx" ON "edu_centers" USING gin ("search_vector");
+CREATE INDEX "courses_search_vector_idx" ON "courses" USING gin ("search_vector");
+
+-- typo tolerant search falls back to trigram similarity of short fields
+CREATE INDEX "edu_centers_name_trgm_idx" ON "edu_centers" USING gin ("name" gin_trgm_ops);
+CREATE INDEX "edu_centers_address_trgm_idx" ON "edu_centers" USING gin ("address" gin_trgm_ops);
+CREATE INDEX "courses_name_trgm_idx" ON "courses" USING gin ("name" gin_trgm_ops);
+CREATE INDEX "courses_teacher_trgm_idx" ON "courses" USING gin ("teacher" gin_trgm_ops);
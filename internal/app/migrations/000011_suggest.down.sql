@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "courses_teacher_prefix_idx";
+DROP INDEX IF EXISTS "courses_name_prefix_idx";
+DROP INDEX IF EXISTS "edu_centers_name_prefix_idx";
@@ -0,0 +1,7 @@
+-- type-ahead looks up names and teachers by prefix
+CREATE INDEX "edu_centers_name_prefix_idx" ON "edu_centers" (lower("name") text_pattern_ops)
+WHERE "deleted_at" IS NULL AND "hidden_at" IS NULL;
+CREATE INDEX "courses_name_prefix_idx" ON "courses" (lower("name") text_pattern_ops)
+WHERE "deleted_at" IS NULL AND "hidden_at" IS NULL;
+CREATE INDEX "courses_teacher_prefix_idx" ON "courses" (lower("teacher") text_pattern_ops)
+WHERE "deleted_at" IS NULL AND "hidden_at" IS NULL;
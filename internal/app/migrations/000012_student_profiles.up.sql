@@ -0,0 +1,17 @@
+-- preferences of student used to match courses, days of week are numbered from 0 (Sunday) to 6
+CREATE TABLE "student_profiles" (
+    "user_id" uuid PRIMARY KEY REFERENCES "users" ("id"),
+    "subjects" text[] NOT NULL DEFAULT '{}',
+    "budget" numeric(12, 2),
+    "currency" char(3),
+    "preferred_days" smallint[] NOT NULL DEFAULT '{}',
+    "preferred_time_from" time,
+    "preferred_time_to" time,
+    "max_distance" double precision,
+    "home_location" POINT,
+    "level" varchar(50),
+    "created_at" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK ("budget" IS NULL OR "currency" IS NOT NULL),
+    CHECK ("preferred_time_from" IS NULL OR "preferred_time_to" > "preferred_time_from")
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "ratings_owner_id_idx";
+DROP TABLE IF EXISTS "item_similarities";
@@ -0,0 +1,16 @@
+-- item to item similarities of rated edu centers and courses, recomputed by background job
+CREATE TABLE "item_similarities" (
+    "item_type" varchar(50) NOT NULL,
+    "item_id" uuid NOT NULL,
+    "similar_type" varchar(50) NOT NULL,
+    "similar_id" uuid NOT NULL,
+    "score" double precision NOT NULL,
+    "common_raters" int NOT NULL,
+    "computed_at" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("item_id", "similar_id"),
+    CHECK ("item_type" IN ('edu_center', 'course')),
+    CHECK ("similar_type" IN ('edu_center', 'course'))
+);
+
+CREATE INDEX "item_similarities_item_score_idx" ON "item_similarities" ("item_id", "score" DESC);
+CREATE INDEX "ratings_owner_id_idx" ON "ratings" ("owner_id");
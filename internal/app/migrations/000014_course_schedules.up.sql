@@ -0,0 +1,28 @@
+-- groups of course running in period of dates, language is BCP 47 tag
+CREATE TABLE "course_schedules" (
+    "id" uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "course_id" uuid NOT NULL REFERENCES "courses" ("id"),
+    "name" varchar(255) NOT NULL DEFAULT '',
+    "start_date" date NOT NULL,
+    "end_date" date,
+    "room" varchar(100) NOT NULL DEFAULT '',
+    "teacher" varchar(255) NOT NULL DEFAULT '',
+    "language" varchar(35) NOT NULL DEFAULT '',
+    "created_at" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK ("end_date" IS NULL OR "end_date" >= "start_date")
+);
+
+-- weekly time slots of group, days of week are numbered from 0 (Sunday) to 6
+CREATE TABLE "schedule_slots" (
+    "id" uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "schedule_id" uuid NOT NULL REFERENCES "course_schedules" ("id") ON DELETE CASCADE,
+    "day_of_week" smallint NOT NULL,
+    "start_time" time NOT NULL,
+    "end_time" time NOT NULL,
+    CHECK ("day_of_week" BETWEEN 0 AND 6),
+    CHECK ("end_time" > "start_time")
+);
+
+CREATE INDEX "course_schedules_course_id_idx" ON "course_schedules" ("course_id");
+CREATE INDEX "schedule_slots_schedule_id_idx" ON "schedule_slots" ("schedule_id", "day_of_week", "start_time");
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS "course_discounts";
+
+ALTER TABLE "courses"
+    DROP COLUMN IF EXISTS "price_unit",
+    DROP COLUMN IF EXISTS "currency",
+    DROP COLUMN IF EXISTS "price";
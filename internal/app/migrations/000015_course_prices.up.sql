@@ -0,0 +1,24 @@
+-- price of course in ISO 4217 currency per month, lesson or the full course
+ALTER TABLE "courses"
+    ADD COLUMN "price" numeric(12, 2),
+    ADD COLUMN "currency" char(3),
+    ADD COLUMN "price_unit" varchar(10),
+    ADD CHECK ("price" IS NULL OR "price" >= 0),
+    ADD CHECK (("price" IS NULL) = ("currency" IS NULL) AND ("price" IS NULL) = ("price_unit" IS NULL)),
+    ADD CHECK ("price_unit" IN ('month', 'lesson', 'course'));
+
+-- discount campaigns of course, discount is applied while campaign runs
+CREATE TABLE "course_discounts" (
+    "id" uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "course_id" uuid NOT NULL REFERENCES "courses" ("id") ON DELETE CASCADE,
+    "name" varchar(255) NOT NULL DEFAULT '',
+    "percent" numeric(5, 2) NOT NULL,
+    "starts_at" TIMESTAMP WITH TIME ZONE NOT NULL,
+    "ends_at" TIMESTAMP WITH TIME ZONE NOT NULL,
+    "created_at" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK ("percent" > 0 AND "percent" <= 100),
+    CHECK ("ends_at" > "starts_at")
+);
+
+CREATE INDEX "course_discounts_course_id_idx" ON "course_discounts" ("course_id", "ends_at");
+CREATE INDEX "courses_currency_price_idx" ON "courses" ("currency", "price") WHERE "price" IS NOT NULL;
@@ -0,0 +1,15 @@
+-- issued refresh tokens, id is the jti claim. Tokens issued by rotation of one login share its family,
+-- rotated token is kept to detect its reuse
+CREATE TABLE "refresh_tokens" (
+    "id" uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "family_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+    "device" varchar(255) NOT NULL DEFAULT '',
+    "issued_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "expires_at" TIMESTAMP WITH TIME ZONE NOT NULL,
+    "rotated_at" TIMESTAMP WITH TIME ZONE,
+    "revoked_at" TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX "refresh_tokens_family_id_idx" ON "refresh_tokens" ("family_id");
+CREATE INDEX "refresh_tokens_user_id_idx" ON "refresh_tokens" ("user_id") WHERE "revoked_at" IS NULL;
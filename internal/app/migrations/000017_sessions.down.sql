@@ -0,0 +1,6 @@
+ALTER INDEX IF EXISTS "refresh_tokens_session_id_idx" RENAME TO "refresh_tokens_family_id_idx";
+ALTER TABLE "refresh_tokens" RENAME COLUMN "session_id" TO "family_id";
+ALTER TABLE "refresh_tokens" ADD COLUMN "device" varchar(255) NOT NULL DEFAULT '';
+UPDATE "refresh_tokens" t SET "device" = s."device" FROM "sessions" s WHERE s."id" = t."family_id";
+
+DROP TABLE IF EXISTS "sessions" CASCADE;
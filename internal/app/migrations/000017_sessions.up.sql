@@ -0,0 +1,26 @@
+-- login sessions of users, refresh tokens of a session are issued by rotation of the token given at login
+CREATE TABLE "sessions" (
+    "id" uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "user_id" uuid NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+    "device" varchar(255) NOT NULL DEFAULT '',
+    "ip" varchar(45) NOT NULL DEFAULT '',
+    "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "last_seen_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "expires_at" TIMESTAMP WITH TIME ZONE NOT NULL,
+    "terminated_at" TIMESTAMP WITH TIME ZONE
+);
+
+-- families of already issued refresh tokens become sessions
+INSERT INTO "sessions" ("id", "user_id", "device", "created_at", "last_seen_at", "expires_at", "terminated_at")
+SELECT "family_id", (array_agg("user_id"))[1], (array_agg("device" ORDER BY "issued_at" DESC))[1],
+MIN("issued_at"), MAX("issued_at"), MAX("expires_at"),
+CASE WHEN bool_and("revoked_at" IS NOT NULL) THEN MAX("revoked_at") END
+FROM "refresh_tokens"
+GROUP BY "family_id";
+
+ALTER TABLE "refresh_tokens" RENAME COLUMN "family_id" TO "session_id";
+ALTER TABLE "refresh_tokens" DROP COLUMN "device";
+ALTER TABLE "refresh_tokens" ADD FOREIGN KEY ("session_id") REFERENCES "sessions" ("id") ON DELETE CASCADE;
+ALTER INDEX "refresh_tokens_family_id_idx" RENAME TO "refresh_tokens_session_id_idx";
+
+CREATE INDEX "sessions_user_id_idx" ON "sessions" ("user_id") WHERE "terminated_at" IS NULL;
@@ -0,0 +1,12 @@
+-- password reset requests, only sha-256 of the token mailed to user is stored.
+-- Token is used at most once and stops working when user asks for a new one.
+CREATE TABLE "password_resets" (
+    "id" uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    "user_id" uuid NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+    "token_hash" char(64) NOT NULL UNIQUE,
+    "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "expires_at" TIMESTAMP WITH TIME ZONE NOT NULL,
+    "used_at" TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX "password_resets_user_id_idx" ON "password_resets" ("user_id") WHERE "used_at" IS NULL;
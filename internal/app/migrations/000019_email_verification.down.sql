@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "users_verified_email_idx";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "verification_sent_at";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "email_verified_at";
@@ -0,0 +1,6 @@
+-- email is verified by signed link mailed to it, changing email makes it unverified again.
+-- Sending time of the last link throttles resending it.
+ALTER TABLE "users" ADD COLUMN "email_verified_at" TIMESTAMP WITH TIME ZONE;
+ALTER TABLE "users" ADD COLUMN "verification_sent_at" TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX "users_verified_email_idx" ON "users" ("email") WHERE "email_verified_at" IS NOT NULL AND "deleted_at" IS NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE "courses" DROP COLUMN IF EXISTS "moderation_deleted_at";
+ALTER TABLE "edu_centers" DROP COLUMN IF EXISTS "moderation_deleted_at";
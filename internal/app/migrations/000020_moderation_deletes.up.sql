@@ -0,0 +1,13 @@
+-- items deleted by moderation are told apart from items their owners deleted, so restoring brings back only the former
+ALTER TABLE "edu_centers" ADD COLUMN "moderation_deleted_at" TIMESTAMP WITH TIME ZONE;
+ALTER TABLE "courses" ADD COLUMN "moderation_deleted_at" TIMESTAMP WITH TIME ZONE;
+
+-- deleted items whose report was resolved by deletion were removed by moderation
+UPDATE "edu_centers" e SET "moderation_deleted_at" = e."deleted_at"
+WHERE e."deleted_at" IS NOT NULL AND EXISTS (
+    SELECT 1 FROM "reports" r WHERE r."target_type" = 'edu_center' AND r."target_id" = e."id" AND r."decision" = 'delete'
+);
+UPDATE "courses" c SET "moderation_deleted_at" = c."deleted_at"
+WHERE c."deleted_at" IS NOT NULL AND EXISTS (
+    SELECT 1 FROM "reports" r WHERE r."target_type" = 'course' AND r."target_id" = c."id" AND r."decision" = 'delete'
+);
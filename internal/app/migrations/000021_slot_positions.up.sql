@@ -0,0 +1,12 @@
+-- position of slot in its group, calendar events of slots are identified by it, so they survive edits of slot times
+ALTER TABLE "schedule_slots" ADD COLUMN "position" smallint;
+
+UPDATE "schedule_slots" s SET "position" = p."position"
+FROM (
+    SELECT "id", ROW_NUMBER() OVER (PARTITION BY "schedule_id" ORDER BY "day_of_week", "start_time", "id") - 1 AS "position"
+    FROM "schedule_slots"
+) p
+WHERE p."id" = s."id";
+
+ALTER TABLE "schedule_slots" ALTER COLUMN "position" SET NOT NULL;
+ALTER TABLE "schedule_slots" ADD CONSTRAINT "schedule_slots_schedule_id_position_key" UNIQUE ("schedule_id", "position");
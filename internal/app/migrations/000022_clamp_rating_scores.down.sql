@@ -0,0 +1 @@
+-- original scores of raised ratings aren't kept, so they stay raised and checked
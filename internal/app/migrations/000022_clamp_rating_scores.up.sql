@@ -0,0 +1,39 @@
+-- scores given when 0 was valid are raised to the lowest star, so they can be changed, hidden and restored
+-- under the check and fall into score buckets. Aggregates of their items are recomputed with raised scores first.
+UPDATE "edu_centers" e
+SET "rating_sum" = a.sum, "rating_count" = a.count, "rating_avg" = a.avg,
+    "score_1" = a.score_1, "score_2" = a.score_2, "score_3" = a.score_3, "score_4" = a.score_4, "score_5" = a.score_5
+FROM (
+    SELECT x.id, COALESCE(SUM(LEAST(GREATEST(r.score, 1), 5)), 0) AS sum, COUNT(r.id) AS count, COALESCE(ROUND(AVG(LEAST(GREATEST(r.score, 1), 5)), 1), 0) AS avg,
+        COUNT(r.id) FILTER (WHERE r.score <= 1) AS score_1,
+        COUNT(r.id) FILTER (WHERE r.score = 2) AS score_2,
+        COUNT(r.id) FILTER (WHERE r.score = 3) AS score_3,
+        COUNT(r.id) FILTER (WHERE r.score = 4) AS score_4,
+        COUNT(r.id) FILTER (WHERE r.score >= 5) AS score_5
+    FROM "edu_centers" x
+    LEFT JOIN "ratings" r ON r.edu_center_id = x.id AND r.hidden_at IS NULL
+    WHERE x.id IN (SELECT edu_center_id FROM "ratings" WHERE "score" NOT BETWEEN 1 AND 5)
+    GROUP BY x.id
+) a
+WHERE e.id = a.id;
+
+UPDATE "courses" c
+SET "rating_sum" = a.sum, "rating_count" = a.count, "rating_avg" = a.avg,
+    "score_1" = a.score_1, "score_2" = a.score_2, "score_3" = a.score_3, "score_4" = a.score_4, "score_5" = a.score_5
+FROM (
+    SELECT x.id, COALESCE(SUM(LEAST(GREATEST(r.score, 1), 5)), 0) AS sum, COUNT(r.id) AS count, COALESCE(ROUND(AVG(LEAST(GREATEST(r.score, 1), 5)), 1), 0) AS avg,
+        COUNT(r.id) FILTER (WHERE r.score <= 1) AS score_1,
+        COUNT(r.id) FILTER (WHERE r.score = 2) AS score_2,
+        COUNT(r.id) FILTER (WHERE r.score = 3) AS score_3,
+        COUNT(r.id) FILTER (WHERE r.score = 4) AS score_4,
+        COUNT(r.id) FILTER (WHERE r.score >= 5) AS score_5
+    FROM "courses" x
+    LEFT JOIN "ratings" r ON r.course_id = x.id AND r.hidden_at IS NULL
+    WHERE x.id IN (SELECT course_id FROM "ratings" WHERE "score" NOT BETWEEN 1 AND 5)
+    GROUP BY x.id
+) a
+WHERE c.id = a.id;
+
+UPDATE "ratings" SET "score" = LEAST(GREATEST("score", 1), 5) WHERE "score" NOT BETWEEN 1 AND 5;
+
+ALTER TABLE "ratings" VALIDATE CONSTRAINT "ratings_score_range";
@@ -0,0 +1 @@
+-- centers with unknown location can't be told from ones created without it, so locations stay unknown
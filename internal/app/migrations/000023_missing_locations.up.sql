@@ -0,0 +1,2 @@
+-- centers created without location were saved at (0,0) before it became optional, their location is unknown
+UPDATE "edu_centers" SET "location" = NULL WHERE "location" ~= POINT(0, 0);
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TimetableSlot is the weekly slot of course group with details shown in calendar events.
+// Dates are "2006-01-02" and times are "15:04", empty EndDate means the group is open ended.
+// Position is the place of slot among slots of its group.
+type TimetableSlot struct {
+	ScheduleID    uuid.UUID `db:"schedule_id"`
+	ScheduleName  string    `db:"schedule_name"`
+	CourseName    string    `db:"course_name"`
+	EduCenterName string    `db:"edu_center_name"`
+	Address       string    `db:"address"`
+	Room          string    `db:"room"`
+	Teacher       string    `db:"teacher"`
+	Language      string    `db:"language"`
+	StartDate     string    `db:"start_date"`
+	EndDate       string    `db:"end_date"`
+	Position      int       `db:"position"`
+	DayOfWeek     int       `db:"day_of_week"`
+	StartTime     string    `db:"start_time"`
+	EndTime       string    `db:"end_time"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+// Timetable is the named set of weekly slots of a course or all courses of an edu center
+type Timetable struct {
+	Name  string
+	Slots []TimetableSlot
+}
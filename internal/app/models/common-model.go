@@ -44,3 +44,25 @@ type Contact struct {
 	Website     string    `json:"website" db:"website"`
 	PhoneNumber string    `json:"phone_number" db:"phone_number"`
 }
+
+// PageQuery holds the paging and sorting parameters shared by listing endpoints.
+// When Cursor is set it takes precedence over Page.
+type PageQuery struct {
+	Page   int    `form:"page" validate:"omitempty,gte=1"`
+	Limit  int    `form:"limit" validate:"omitempty,gte=1,lte=100"`
+	Cursor string `form:"cursor"`
+	Order  string `form:"order" validate:"omitempty,oneof=asc desc"`
+}
+
+// Pagination is the paging metadata returned together with listings
+type Pagination struct {
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+const (
+	DefaultPageLimit = 20
+	OrderAsc         = "asc"
+	OrderDesc        = "desc"
+)
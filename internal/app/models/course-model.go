@@ -7,37 +7,75 @@ import (
 )
 
 type CreateCourseDto struct {
-	Name        string    `json:"name" db:"name"`
-	Description string    `json:"description" db:"description"`
-	Teacher     string    `json:"teacher" db:"teacher"`
-	EduCenterID uuid.UUID `json:"edu_center_id" db:"edu_center_id"`
+	Name        string          `json:"name" db:"name"`
+	Description string          `json:"description" db:"description"`
+	Teacher     string          `json:"teacher" db:"teacher"`
+	EduCenterID uuid.UUID       `json:"edu_center_id" db:"edu_center_id"`
+	Price       *CoursePriceDto `json:"price"`
 }
 type UpdateCourseDto struct {
-	ID          uuid.UUID `json:"id" db:"id"`
-	Name        string    `json:"name" db:"name"`
-	Description string    `json:"description" db:"description"`
-	Teacher     string    `json:"teacher" db:"teacher"`
-	EduCenterID uuid.UUID `json:"edu_center_id" db:"edu_center_id"`
-	UpdatedAt   time.Time `json:"-" db:"updated_at"`
+	ID          uuid.UUID       `json:"id" db:"id"`
+	Name        string          `json:"name" db:"name"`
+	Description string          `json:"description" db:"description"`
+	Teacher     string          `json:"teacher" db:"teacher"`
+	EduCenterID uuid.UUID       `json:"edu_center_id" db:"edu_center_id"`
+	Price       *CoursePriceDto `json:"price"`
+	UpdatedAt   time.Time       `json:"-" db:"updated_at"`
 }
 type Course struct {
-	ID          uuid.UUID `json:"id" db:"id"`
-	Name        string    `json:"name" db:"name"`
-	Description string    `json:"description" db:"description"`
-	Teacher     string    `json:"teacher" db:"teacher"`
-	EduCenterID uuid.UUID `json:"edu_center_id" db:"edu_center_id"`
-	Rating      float64   `json:"rating" db:"rating"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID          uuid.UUID    `json:"id" db:"id"`
+	Name        string       `json:"name" db:"name"`
+	Description string       `json:"description" db:"description"`
+	Teacher     string       `json:"teacher" db:"teacher"`
+	EduCenterID uuid.UUID    `json:"edu_center_id" db:"edu_center_id"`
+	Rating      float64      `json:"rating" db:"rating"`
+	Price       *CoursePrice `json:"price"`
+	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at" db:"updated_at"`
+	RatingStats
 }
 type AllCourses struct {
-	Count   int      `json:"count"`
+	Count int `json:"count"`
+	Pagination
 	Courses []Course `json:"courses"`
 }
 
+// sort keys of course listing
+const (
+	CourseSortRating    = "rating"
+	CourseSortCreatedAt = "created_at"
+	CourseSortPrice     = "price"
+)
+
+// CourseQuery is the filter, sort and paging parameters of course listing
+type CourseQuery struct {
+	PageQuery
+	SortBy      string  `form:"sort_by" validate:"omitempty,oneof=rating created_at price"`
+	EduCenterID string  `form:"edu_center_id" validate:"omitempty,uuid"`
+	Teacher     string  `form:"teacher"`
+	MinRating   float64 `form:"min_rating" validate:"gte=0,lte=5"`
+	MaxRating   float64 `form:"max_rating" validate:"omitempty,gte=0,lte=5,gtefield=MinRating"`
+	Text        string  `form:"q"`
+	Day         *int    `form:"day" validate:"omitempty,gte=0,lte=6"`
+	TimeFrom    string  `form:"time_from" validate:"omitempty,datetime=15:04"`
+	TimeTo      string  `form:"time_to" validate:"omitempty,datetime=15:04"`
+	Currency    string  `form:"currency" validate:"required_with=MinPrice MaxPrice,omitempty,iso4217"`
+	PriceUnit   string  `form:"price_unit" validate:"required_with=MinPrice MaxPrice,omitempty,oneof=month lesson course"`
+	MinPrice    float64 `form:"min_price" validate:"gte=0"`
+	MaxPrice    float64 `form:"max_price" validate:"omitempty,gte=0,gtefield=MinPrice"`
+}
+
+// CourseSummary is the short overview of courses of an edu center
+type CourseSummary struct {
+	Count    int      `json:"count" db:"count"`
+	TopRated []Course `json:"top_rated"`
+}
+
 type CourseRating struct {
-	ID       uuid.UUID `json:"-" db:"id"`
-	Score    uint8     `json:"score" db:"score" validate:"gte=0,lte=5"`
-	OwnerID  uuid.UUID `json:"-" db:"owner_id"`
-	CourseID uuid.UUID `json:"course_id" db:"course_id"`
+	ID        uuid.UUID `json:"id" db:"id"`
+	Score     uint8     `json:"score" db:"score" validate:"gte=1,lte=5"`
+	OwnerID   uuid.UUID `json:"owner_id" db:"owner_id"`
+	CourseID  uuid.UUID `json:"course_id" db:"course_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
@@ -8,24 +8,27 @@ import (
 )
 
 type EduCenter struct {
-	ID              uuid.UUID `json:"id" db:"id"`
-	Name            string    `json:"name" db:"name" validate:"required"`
-	HtmlDescription string    `json:"html_description" db:"html_description"`
-	Address         string    `json:"address" db:"address"`
-	Location        Point     `json:"location" db:"location" binding:"required"`
-	OwnerID         uuid.UUID `json:"owner_id" db:"owner_id"`
-	CoverImage      string    `json:"cover_image" db:"cover_image"`
-	Rating          float64   `json:"rating" db:"rating"`
-	Contacts        Contact   `json:"contacts"`
-	CreatedAt       time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+	ID              uuid.UUID      `json:"id" db:"id"`
+	Name            string         `json:"name" db:"name" validate:"required"`
+	HtmlDescription string         `json:"html_description" db:"html_description"`
+	Address         string         `json:"address" db:"address"`
+	Location        *Point         `json:"location" db:"location"`
+	OwnerID         uuid.UUID      `json:"owner_id" db:"owner_id"`
+	CoverImage      string         `json:"cover_image" db:"cover_image"`
+	Rating          float64        `json:"rating" db:"rating"`
+	Contacts        Contact        `json:"contacts"`
+	Distance        *float64       `json:"distance,omitempty" db:"distance"`
+	Courses         *CourseSummary `json:"courses,omitempty"`
+	CreatedAt       time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at" db:"updated_at"`
+	RatingStats
 }
 
 type CreateEduCenterDto struct {
 	Name            string                `form:"name" db:"name" validate:"required"`
 	HtmlDescription string                `form:"html_description" db:"html_description"`
 	Address         string                `form:"address" db:"address"`
-	Location        Point                 `form:"location" db:"location"`
+	Location        *Point                `form:"location" db:"location"`
 	CoverImageUrl   string                `db:"cover_image"`
 	CoverImage      *multipart.FileHeader `form:"cover_image" db:"-"`
 	OwnerID         uuid.UUID             `db:"owner_id"`
@@ -37,7 +40,7 @@ type UpdateEduCenterDto struct {
 	Name            string                `form:"name" db:"name"`
 	HtmlDescription string                `form:"html_description" db:"html_description"`
 	Address         string                `form:"address" db:"address"`
-	Location        Point                 `form:"location" db:"location"`
+	Location        *Point                `form:"location" db:"location"`
 	CoverImageUrl   string                `db:"cover_image"`
 	CoverImage      *multipart.FileHeader `form:"cover_image" db:"-"`
 	OldCoverImage   string                `form:"old_cover_image"`
@@ -53,23 +56,54 @@ type EduCenterImages struct {
 }
 
 type AllEduCenters struct {
-	Count      int         `json:"count" db:"count"`
+	Count int `json:"count" db:"count"`
+	Pagination
 	EduCenters []EduCenter `json:"edu_centers"`
 }
 
+// sort keys of edu center listing
+const (
+	EduCenterSortRating    = "rating"
+	EduCenterSortName      = "name"
+	EduCenterSortCreatedAt = "created_at"
+	EduCenterSortDistance  = "distance"
+)
+
+// EduCenterFilter is the filters shared by edu center listing and map
+type EduCenterFilter struct {
+	MinRating    float64   `form:"min_rating" validate:"gte=0,lte=5"`
+	HasContacts  *bool     `form:"has_contacts"`
+	NamePrefix   string    `form:"name_prefix"`
+	CreatedAfter time.Time `form:"created_after"`
+}
+
+// EduCenterQuery is the filter, sort and paging parameters of edu center listing.
+// Latitude and Longitude are required for sorting by distance.
+type EduCenterQuery struct {
+	PageQuery
+	EduCenterFilter
+	SortBy    string   `form:"sort_by" validate:"omitempty,oneof=rating name created_at distance"`
+	Latitude  *float64 `form:"latitude" validate:"required_with=Longitude,omitempty,gte=-90,lte=90"`
+	Longitude *float64 `form:"longitude" validate:"required_with=Latitude,omitempty,gte=-180,lte=180"`
+}
+
 type EduCenterRating struct {
-	ID          uuid.UUID `db:"id"`
-	Score       uint8     `json:"score" db:"score" validate:"gte=0,lte=5"`
-	OwnerID     uuid.UUID `db:"owner_id"`
+	ID          uuid.UUID `json:"id" db:"id"`
+	Score       uint8     `json:"score" db:"score" validate:"gte=1,lte=5"`
+	OwnerID     uuid.UUID `json:"owner_id" db:"owner_id"`
 	EduCenterID uuid.UUID `json:"edu_center_id" db:"edu_center_id"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// NearEduCenterDto is the search of centers around point, Distance is the radius in km (0 means unlimited)
 type NearEduCenterDto struct {
-	Latitude  float64 `json:"latitude"`
-	Longitude float64 `json:"longitude"`
-	Distance  float64 `json:"distance"`
-	Limit     int     `json:"limit"`
-	Offset    int     `json:"offset"`
+	Latitude  float64 `json:"latitude" validate:"gte=-90,lte=90"`
+	Longitude float64 `json:"longitude" validate:"gte=-180,lte=180"`
+	Distance  float64 `json:"distance" validate:"gte=0"`
+	Limit     int     `json:"limit" validate:"gte=0,lte=100"`
+	Offset    int     `json:"offset" validate:"gte=0"`
+	SortBy    string  `json:"sort_by" validate:"omitempty,oneof=distance rating" enums:"distance,rating"`
 }
 
 type NearEduCenter struct {
@@ -80,8 +114,11 @@ type NearEduCenter struct {
 	Location        Point     `json:"location" db:"location" binding:"required"`
 	OwnerID         uuid.UUID `json:"owner_id" db:"owner_id"`
 	Distance        float64   `json:"distance" db:"distance"`
+	Rating          float64   `json:"rating" db:"rating"`
+	Contacts        Contact   `json:"contacts"`
 	CreatedAt       time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+	RatingStats
 }
 
 type AllNearEduCenters struct {
@@ -0,0 +1,43 @@
+package models
+
+import "github.com/google/uuid"
+
+// EduCenterMapQuery selects centers inside map viewport.
+// Bbox is "min_longitude,min_latitude,max_longitude,max_latitude", zoom is the web map zoom level.
+type EduCenterMapQuery struct {
+	EduCenterFilter
+	Bbox string `form:"bbox" validate:"required"`
+	Zoom *int   `form:"zoom" validate:"required,gte=0,lte=22"`
+}
+
+type BoundingBox struct {
+	MinLatitude  float64
+	MinLongitude float64
+	MaxLatitude  float64
+	MaxLongitude float64
+}
+
+// MapEduCenter is the short info of center shown on map
+type MapEduCenter struct {
+	ID             uuid.UUID `json:"id"`
+	Name           string    `json:"name"`
+	Address        string    `json:"address"`
+	Location       Point     `json:"location"`
+	Rating         float64   `json:"rating"`
+	RatingCount    int       `json:"rating_count"`
+	WeightedRating float64   `json:"weighted_rating"`
+}
+
+// MapCluster is a group of centers close to each other at current zoom
+type MapCluster struct {
+	Count    int          `json:"count"`
+	Centroid Point        `json:"centroid"`
+	TopRated MapEduCenter `json:"top_rated"`
+}
+
+// EduCenterMap is the content of map viewport, centers without neighbours are not clustered
+type EduCenterMap struct {
+	Zoom       int            `json:"zoom"`
+	Clusters   []MapCluster   `json:"clusters"`
+	EduCenters []MapEduCenter `json:"edu_centers"`
+}
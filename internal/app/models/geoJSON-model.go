@@ -0,0 +1,99 @@
+package models
+
+import "github.com/google/uuid"
+
+const GeoJSONContentType = "application/geo+json"
+
+// GeoJSONPoint is the geometry of feature, coordinates are [longitude, latitude]
+type GeoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+type EduCenterProperties struct {
+	Name           string   `json:"name"`
+	Address        string   `json:"address"`
+	Rating         float64  `json:"rating"`
+	WeightedRating float64  `json:"weighted_rating"`
+	RatingCount    int      `json:"rating_count"`
+	Contacts       Contact  `json:"contacts"`
+	Distance       *float64 `json:"distance,omitempty"`
+}
+
+type EduCenterFeature struct {
+	Type       string              `json:"type"`
+	ID         uuid.UUID           `json:"id"`
+	Geometry   *GeoJSONPoint       `json:"geometry"`
+	Properties EduCenterProperties `json:"properties"`
+}
+
+// EduCenterFeatureCollection is GeoJSON of centers, count and paging are kept as foreign members
+type EduCenterFeatureCollection struct {
+	Type  string `json:"type"`
+	Count int    `json:"count"`
+	Pagination
+	Features []EduCenterFeature `json:"features"`
+}
+
+// NewGeoJSONPoint returns geometry of point, missing location gives null geometry
+func NewGeoJSONPoint(point *Point) *GeoJSONPoint {
+	if point == nil {
+		return nil
+	}
+	return &GeoJSONPoint{
+		Type:        "Point",
+		Coordinates: [2]float64{point.Longitude, point.Latitude},
+	}
+}
+
+func NewEduCenterFeatureCollection(eduCenters AllEduCenters) EduCenterFeatureCollection {
+	collection := EduCenterFeatureCollection{
+		Type:       "FeatureCollection",
+		Count:      eduCenters.Count,
+		Pagination: eduCenters.Pagination,
+		Features:   []EduCenterFeature{},
+	}
+	for _, eduCenter := range eduCenters.EduCenters {
+		collection.Features = append(collection.Features, EduCenterFeature{
+			Type:     "Feature",
+			ID:       eduCenter.ID,
+			Geometry: NewGeoJSONPoint(eduCenter.Location),
+			Properties: EduCenterProperties{
+				Name:           eduCenter.Name,
+				Address:        eduCenter.Address,
+				Rating:         eduCenter.Rating,
+				WeightedRating: eduCenter.WeightedRating,
+				RatingCount:    eduCenter.RatingCount,
+				Contacts:       eduCenter.Contacts,
+				Distance:       eduCenter.Distance,
+			},
+		})
+	}
+	return collection
+}
+
+func NewNearEduCenterFeatureCollection(eduCenters AllNearEduCenters) EduCenterFeatureCollection {
+	collection := EduCenterFeatureCollection{
+		Type:     "FeatureCollection",
+		Count:    eduCenters.Count,
+		Features: []EduCenterFeature{},
+	}
+	for _, eduCenter := range eduCenters.EduCenters {
+		distance := eduCenter.Distance
+		collection.Features = append(collection.Features, EduCenterFeature{
+			Type:     "Feature",
+			ID:       eduCenter.ID,
+			Geometry: NewGeoJSONPoint(&eduCenter.Location),
+			Properties: EduCenterProperties{
+				Name:           eduCenter.Name,
+				Address:        eduCenter.Address,
+				Rating:         eduCenter.Rating,
+				WeightedRating: eduCenter.WeightedRating,
+				RatingCount:    eduCenter.RatingCount,
+				Contacts:       eduCenter.Contacts,
+				Distance:       &distance,
+			},
+		})
+	}
+	return collection
+}
@@ -0,0 +1,32 @@
+package models
+
+// kinds of gazetteer places, from the widest to the most precise
+const (
+	PlaceCity     = "city"
+	PlaceDistrict = "district"
+	PlaceStreet   = "street"
+)
+
+// Place is an entry of gazetteer, District and City are the places it belongs to
+type Place struct {
+	Kind     string
+	Names    []string
+	District string
+	City     string
+	Location Point
+}
+
+// GeocodedAddress is the place found around point, Distance is in km from the given point to the place centroid
+type GeocodedAddress struct {
+	Address  string  `json:"address"`
+	Street   string  `json:"street,omitempty"`
+	District string  `json:"district,omitempty"`
+	City     string  `json:"city,omitempty"`
+	Location Point   `json:"location"`
+	Distance float64 `json:"distance"`
+}
+
+type ReverseGeocodeQuery struct {
+	Latitude  *float64 `form:"latitude" validate:"required,gte=-90,lte=90"`
+	Longitude *float64 `form:"longitude" validate:"required,gte=-180,lte=180"`
+}
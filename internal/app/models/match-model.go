@@ -0,0 +1,91 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// levels of students and courses
+const (
+	LevelBeginner          = "beginner"
+	LevelElementary        = "elementary"
+	LevelIntermediate      = "intermediate"
+	LevelUpperIntermediate = "upper_intermediate"
+	LevelAdvanced          = "advanced"
+)
+
+// StudentProfile is the preferences courses and edu centers are matched by. Budget is the monthly price student can pay,
+// PreferredDays are days of week from 0 (Sunday) to 6, times are "15:04" and MaxDistance is in km from HomeLocation.
+type StudentProfile struct {
+	UserID            uuid.UUID `json:"user_id" db:"user_id"`
+	Subjects          []string  `json:"subjects" db:"subjects"`
+	Budget            *float64  `json:"budget" db:"budget"`
+	Currency          string    `json:"currency" db:"currency"`
+	PreferredDays     []int     `json:"preferred_days" db:"preferred_days"`
+	PreferredTimeFrom string    `json:"preferred_time_from" db:"preferred_time_from"`
+	PreferredTimeTo   string    `json:"preferred_time_to" db:"preferred_time_to"`
+	MaxDistance       *float64  `json:"max_distance" db:"max_distance"`
+	HomeLocation      *Point    `json:"home_location" db:"home_location"`
+	Level             string    `json:"level" db:"level"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type StudentProfileDto struct {
+	UserID            uuid.UUID `json:"-"`
+	Subjects          []string  `json:"subjects" validate:"max=10,dive,required,max=100"`
+	Budget            *float64  `json:"budget" validate:"omitempty,gt=0"`
+	Currency          string    `json:"currency" validate:"required_with=Budget,omitempty,iso4217"`
+	PreferredDays     []int     `json:"preferred_days" validate:"max=7,unique,dive,gte=0,lte=6"`
+	PreferredTimeFrom string    `json:"preferred_time_from" validate:"required_with=PreferredTimeTo,omitempty,datetime=15:04"`
+	PreferredTimeTo   string    `json:"preferred_time_to" validate:"required_with=PreferredTimeFrom,omitempty,datetime=15:04"`
+	MaxDistance       *float64  `json:"max_distance" validate:"omitempty,gt=0"`
+	HomeLocation      *Point    `json:"home_location"`
+	Level             string    `json:"level" validate:"omitempty,oneof=beginner elementary intermediate upper_intermediate advanced"`
+}
+
+// types of matches
+const (
+	MatchTypeEduCenter = "edu_center"
+	MatchTypeCourse    = "course"
+)
+
+// MatchQuery is the page of matches of one type or of both, ordered by score
+type MatchQuery struct {
+	Type string `form:"type" validate:"omitempty,oneof=edu_center course"`
+	PageQuery
+}
+
+// MatchScores is the breakdown of match score, every factor is from 0 to 1.
+// Factors which can't be evaluated for the match or the profile are null.
+type MatchScores struct {
+	Subjects *float64 `json:"subjects" db:"subjects"`
+	Distance *float64 `json:"distance" db:"distance"`
+	Rating   *float64 `json:"rating" db:"rating"`
+	Schedule *float64 `json:"schedule" db:"schedule"`
+	Price    *float64 `json:"price" db:"price"`
+	Level    *float64 `json:"level" db:"level"`
+}
+
+// Match is the course or edu center matched to student, Score is the weighted mean of evaluated factors.
+// Edu centers are matched by their best fitting courses, EduCenterID of them is their own id.
+type Match struct {
+	Type          string      `json:"type" db:"type"`
+	ID            uuid.UUID   `json:"id" db:"id"`
+	Name          string      `json:"name" db:"name"`
+	Teacher       string      `json:"teacher,omitempty" db:"teacher"`
+	EduCenterID   uuid.UUID   `json:"edu_center_id" db:"edu_center_id"`
+	EduCenterName string      `json:"edu_center_name" db:"edu_center_name"`
+	Location      Point       `json:"location" db:"location"`
+	Distance      *float64    `json:"distance,omitempty" db:"distance"`
+	Rating        float64     `json:"rating" db:"rating"`
+	Score         float64     `json:"score" db:"score"`
+	Scores        MatchScores `json:"scores" db:"scores"`
+}
+
+type Matches struct {
+	Count int `json:"count"`
+	Pagination
+	Matches []Match `json:"matches"`
+}
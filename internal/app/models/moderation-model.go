@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReportTarget is the kind of item which can be reported
+type ReportTarget string
+
+const (
+	ReportEduCenter ReportTarget = "edu_center"
+	ReportCourse    ReportTarget = "course"
+	ReportRating    ReportTarget = "rating"
+)
+
+// report statuses
+const (
+	ReportPending  = "pending"
+	ReportResolved = "resolved"
+)
+
+// moderation actions
+const (
+	ModerationHide    = "hide"
+	ModerationRestore = "restore"
+	ModerationDelete  = "delete"
+	ModerationDismiss = "dismiss"
+)
+
+type CreateReportDto struct {
+	TargetType ReportTarget `json:"target_type" validate:"required,oneof=edu_center course rating"`
+	TargetID   uuid.UUID    `json:"target_id" validate:"required"`
+	Reason     string       `json:"reason" validate:"required,max=1000"`
+	ReporterID uuid.UUID    `json:"-"`
+}
+
+type Report struct {
+	ID           uuid.UUID    `json:"id" db:"id"`
+	ReporterID   uuid.UUID    `json:"reporter_id" db:"reporter_id"`
+	TargetType   ReportTarget `json:"target_type" db:"target_type"`
+	TargetID     uuid.UUID    `json:"target_id" db:"target_id"`
+	Reason       string       `json:"reason" db:"reason"`
+	Status       string       `json:"status" db:"status"`
+	Decision     *string      `json:"decision" db:"decision"`
+	DecisionNote *string      `json:"decision_note" db:"decision_note"`
+	DecidedBy    *uuid.UUID   `json:"decided_by" db:"decided_by"`
+	DecidedAt    *time.Time   `json:"decided_at" db:"decided_at"`
+	CreatedAt    time.Time    `json:"created_at" db:"created_at"`
+}
+
+type ModerationDecisionDto struct {
+	Action      string    `json:"action" validate:"required,oneof=hide restore delete dismiss"`
+	Note        string    `json:"note" validate:"max=1000"`
+	ModeratorID uuid.UUID `json:"-"`
+}
+
+type ReportQuery struct {
+	PageQuery
+	Status     string `form:"status" validate:"omitempty,oneof=pending resolved"`
+	TargetType string `form:"target_type" validate:"omitempty,oneof=edu_center course rating"`
+}
+
+type AllReports struct {
+	Count int `json:"count"`
+	Pagination
+	Reports []Report `json:"reports"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// units courses are priced in
+const (
+	PricePerMonth  = "month"
+	PricePerLesson = "lesson"
+	PricePerCourse = "course"
+)
+
+// CoursePrice is the price of course in ISO 4217 currency per Unit.
+// Effective is the price after Discount, the best discount running at request time, rounded to cents by the database.
+type CoursePrice struct {
+	Amount    float64         `json:"amount"`
+	Currency  string          `json:"currency"`
+	Unit      string          `json:"unit"`
+	Effective float64         `json:"effective"`
+	Discount  *CourseDiscount `json:"discount"`
+}
+
+type CoursePriceDto struct {
+	Amount   float64 `json:"amount" validate:"gte=0,lt=10000000000"`
+	Currency string  `json:"currency" validate:"required,iso4217"`
+	Unit     string  `json:"unit" validate:"required,oneof=month lesson course"`
+}
+
+// CourseDiscount is the discount campaign of course in percents, running from StartsAt till EndsAt
+type CourseDiscount struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	CourseID  uuid.UUID `json:"course_id" db:"course_id"`
+	Name      string    `json:"name" db:"name"`
+	Percent   float64   `json:"percent" db:"percent"`
+	StartsAt  time.Time `json:"starts_at" db:"starts_at"`
+	EndsAt    time.Time `json:"ends_at" db:"ends_at"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type CourseDiscountDto struct {
+	CourseID uuid.UUID `json:"-"`
+	Name     string    `json:"name" validate:"max=255"`
+	Percent  float64   `json:"percent" validate:"gt=0,lte=100"`
+	StartsAt time.Time `json:"starts_at" validate:"required"`
+	EndsAt   time.Time `json:"ends_at" validate:"required,gtfield=StartsAt"`
+}
+
+type CourseDiscounts struct {
+	Discounts []CourseDiscount `json:"discounts"`
+}
@@ -0,0 +1,24 @@
+package models
+
+// RatingDistribution is the number of ratings given with each score
+type RatingDistribution struct {
+	Score1 int `json:"1" db:"score_1"`
+	Score2 int `json:"2" db:"score_2"`
+	Score3 int `json:"3" db:"score_3"`
+	Score4 int `json:"4" db:"score_4"`
+	Score5 int `json:"5" db:"score_5"`
+}
+
+// RatingStats summarizes visible ratings of center or course.
+// WeightedRating is the bayesian average which pulls scores with few votes towards the mean of all ratings.
+type RatingStats struct {
+	RatingCount    int                `json:"rating_count" db:"rating_count"`
+	WeightedRating float64            `json:"weighted_rating" db:"weighted_rating"`
+	Distribution   RatingDistribution `json:"rating_distribution"`
+}
+
+// RepairedAggregates is the number of items whose stored rating aggregates were out of date
+type RepairedAggregates struct {
+	EduCenters int64 `json:"edu_centers"`
+	Courses    int64 `json:"courses"`
+}
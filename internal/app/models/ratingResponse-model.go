@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RatingResponse is the public answer of center owner to a rating
+type RatingResponse struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	RatingID  uuid.UUID `json:"rating_id" db:"rating_id"`
+	OwnerID   uuid.UUID `json:"owner_id" db:"owner_id"`
+	Body      string    `json:"body" db:"body"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type RatingResponseDto struct {
+	Body    string    `json:"body" validate:"required,max=2000"`
+	OwnerID uuid.UUID `json:"-"`
+}
+
+// RatingWithResponse is a rating of center or of its course together with owner response
+type RatingWithResponse struct {
+	ID          uuid.UUID       `json:"id"`
+	Score       uint8           `json:"score"`
+	TargetType  RatingTarget    `json:"target_type"`
+	TargetID    uuid.UUID       `json:"target_id"`
+	EduCenterID uuid.UUID       `json:"edu_center_id"`
+	Rater       Reviewer        `json:"rater"`
+	Response    *RatingResponse `json:"response"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+type AllRatingsWithResponses struct {
+	Count int `json:"count"`
+	Pagination
+	Ratings []RatingWithResponse `json:"ratings"`
+}
+
+// default highest score of rating considered low
+const DefaultLowRatingScore = 2
+
+type UnansweredRatingsQuery struct {
+	PageQuery
+	MaxScore int `form:"max_score" validate:"omitempty,gte=1,lte=5"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import "github.com/google/uuid"
+
+// reasons of recommendations
+const (
+	RecommendationSimilar = "similar"
+	RecommendationPopular = "popular"
+)
+
+const DefaultRecommendationLimit = 10
+
+// RecommendationQuery is the area popular items are recommended from when user has no rating history,
+// home location of student profile is used when Latitude and Longitude are not given
+type RecommendationQuery struct {
+	Latitude  *float64 `form:"latitude" validate:"required_with=Longitude,omitempty,gte=-90,lte=90"`
+	Longitude *float64 `form:"longitude" validate:"required_with=Latitude,omitempty,gte=-180,lte=180"`
+	Limit     int      `form:"limit" validate:"omitempty,gte=1,lte=50"`
+}
+
+type SimilarQuery struct {
+	Limit int `form:"limit" validate:"omitempty,gte=1,lte=50"`
+}
+
+// Recommendation is the recommended edu center or course. Score is the similarity to items user rated
+// (or to the given course), for popular items it is their weighted rating.
+type Recommendation struct {
+	Type        RatingTarget `json:"type" db:"type"`
+	ID          uuid.UUID    `json:"id" db:"id"`
+	Name        string       `json:"name" db:"name"`
+	EduCenterID uuid.UUID    `json:"edu_center_id" db:"edu_center_id"`
+	Location    Point        `json:"location" db:"location"`
+	Rating      float64      `json:"rating" db:"rating"`
+	RatingCount int          `json:"rating_count" db:"rating_count"`
+	Score       float64      `json:"score" db:"score"`
+	Reason      string       `json:"reason" db:"reason"`
+}
+
+type Recommendations struct {
+	Recommendations []Recommendation `json:"recommendations"`
+}
+
+// ComputedSimilarities is the result of similarity recomputation
+type ComputedSimilarities struct {
+	Items        int64 `json:"items"`
+	Similarities int64 `json:"similarities"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RatingTarget is the kind of item a rating belongs to
+type RatingTarget string
+
+const (
+	EduCenterTarget RatingTarget = "edu_center"
+	CourseTarget    RatingTarget = "course"
+)
+
+// Reviewer is the public info of user who wrote the review
+type Reviewer struct {
+	ID        uuid.UUID `json:"id" db:"reviewer_id"`
+	FirstName string    `json:"first_name" db:"first_name"`
+	LastName  string    `json:"last_name" db:"last_name"`
+	Username  string    `json:"username" db:"username"`
+	Avatar    string    `json:"avatar" db:"avatar"`
+}
+
+type Review struct {
+	ID           uuid.UUID `json:"id" db:"id"`
+	RatingID     uuid.UUID `json:"rating_id" db:"rating_id"`
+	Score        uint8     `json:"score" db:"score"`
+	Title        string    `json:"title" db:"title"`
+	Body         string    `json:"body" db:"body"`
+	Pros         string    `json:"pros" db:"pros"`
+	Cons         string    `json:"cons" db:"cons"`
+	HelpfulCount int       `json:"helpful_count" db:"helpful_count"`
+	Reviewer     Reviewer  `json:"reviewer"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type ReviewDto struct {
+	Title string `json:"title" validate:"required,max=255"`
+	Body  string `json:"body" validate:"required,max=5000"`
+	Pros  string `json:"pros" validate:"max=1000"`
+	Cons  string `json:"cons" validate:"max=1000"`
+}
+
+type AllReviews struct {
+	Count int `json:"count"`
+	Pagination
+	Reviews []Review `json:"reviews"`
+}
+
+// sort keys of review listing
+const (
+	ReviewSortNewest  = "newest"
+	ReviewSortHelpful = "helpful"
+)
+
+type ReviewQuery struct {
+	PageQuery
+	SortBy string `form:"sort_by" validate:"omitempty,oneof=newest helpful"`
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduleSlot is the weekly lesson of group, DayOfWeek is from 0 (Sunday) to 6 and times are "15:04"
+type ScheduleSlot struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	ScheduleID uuid.UUID `json:"-" db:"schedule_id"`
+	DayOfWeek  int       `json:"day_of_week" db:"day_of_week"`
+	StartTime  string    `json:"start_time" db:"start_time"`
+	EndTime    string    `json:"end_time" db:"end_time"`
+}
+
+// CourseSchedule is the group of course running from StartDate till EndDate ("2006-01-02", empty when open ended).
+// Empty Teacher means the group is taught by teacher of the course.
+type CourseSchedule struct {
+	ID        uuid.UUID      `json:"id" db:"id"`
+	CourseID  uuid.UUID      `json:"course_id" db:"course_id"`
+	Name      string         `json:"name" db:"name"`
+	StartDate string         `json:"start_date" db:"start_date"`
+	EndDate   string         `json:"end_date" db:"end_date"`
+	Room      string         `json:"room" db:"room"`
+	Teacher   string         `json:"teacher" db:"teacher"`
+	Language  string         `json:"language" db:"language"`
+	Slots     []ScheduleSlot `json:"slots"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+type ScheduleSlotDto struct {
+	DayOfWeek *int   `json:"day_of_week" validate:"required,gte=0,lte=6"`
+	StartTime string `json:"start_time" validate:"required,datetime=15:04"`
+	EndTime   string `json:"end_time" validate:"required,datetime=15:04"`
+}
+
+type CourseScheduleDto struct {
+	ID        uuid.UUID         `json:"-"`
+	CourseID  uuid.UUID         `json:"-"`
+	Name      string            `json:"name" validate:"max=255"`
+	StartDate string            `json:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate   string            `json:"end_date" validate:"omitempty,datetime=2006-01-02"`
+	Room      string            `json:"room" validate:"max=100"`
+	Teacher   string            `json:"teacher" validate:"max=255"`
+	Language  string            `json:"language" validate:"omitempty,bcp47_language_tag"`
+	Slots     []ScheduleSlotDto `json:"slots" validate:"required,min=1,max=21,dive"`
+}
+
+type CourseSchedules struct {
+	Schedules []CourseSchedule `json:"schedules"`
+}
+
+// CourseCenter is the edu center course belongs to together with teacher of course
+type CourseCenter struct {
+	EduCenterID uuid.UUID `db:"edu_center_id"`
+	OwnerID     uuid.UUID `db:"owner_id"`
+	Teacher     string    `db:"teacher"`
+}
+
+// ScheduleConflict is the slot of another group in the same room or with the same teacher overlapping a new slot
+type ScheduleConflict struct {
+	ScheduleName string `db:"schedule_name"`
+	CourseName   string `db:"course_name"`
+	SameRoom     bool   `db:"same_room"`
+	DayOfWeek    int    `db:"day_of_week"`
+	StartTime    string `db:"start_time"`
+	EndTime      string `db:"end_time"`
+}
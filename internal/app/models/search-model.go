@@ -0,0 +1,72 @@
+package models
+
+import "github.com/google/uuid"
+
+// types of search results
+const (
+	SearchTypeEduCenter = "edu_center"
+	SearchTypeCourse    = "course"
+)
+
+// SearchQuery is the text search of centers and courses. Distance is the radius in km around
+// Latitude and Longitude (0 means unlimited), courses are placed at location of their center.
+// Results are ordered by rank.
+type SearchQuery struct {
+	Text      string   `form:"q" validate:"required,max=200"`
+	Type      string   `form:"type" validate:"omitempty,oneof=edu_center course"`
+	MinRating float64  `form:"min_rating" validate:"gte=0,lte=5"`
+	Latitude  *float64 `form:"latitude" validate:"required_with=Longitude,omitempty,gte=-90,lte=90"`
+	Longitude *float64 `form:"longitude" validate:"required_with=Latitude,omitempty,gte=-180,lte=180"`
+	Distance  float64  `form:"distance" validate:"gte=0"`
+	PageQuery
+}
+
+// SearchResult is the matched center or course. Highlights holds fragments of matched fields
+// with matches wrapped in <mark> tags.
+type SearchResult struct {
+	Type        string            `json:"type" db:"type"`
+	ID          uuid.UUID         `json:"id" db:"id"`
+	Name        string            `json:"name" db:"name"`
+	EduCenterID uuid.UUID         `json:"edu_center_id" db:"edu_center_id"`
+	Location    Point             `json:"location" db:"location"`
+	Rating      float64           `json:"rating" db:"rating"`
+	Distance    *float64          `json:"distance,omitempty" db:"distance"`
+	Rank        float64           `json:"rank" db:"rank"`
+	Highlights  map[string]string `json:"highlights,omitempty"`
+}
+
+// SearchResults is the page of search results, Fuzzy is set when nothing matched
+// the words exactly and results are found by similarity
+type SearchResults struct {
+	Count int  `json:"count"`
+	Fuzzy bool `json:"fuzzy"`
+	Pagination
+	Results []SearchResult `json:"results"`
+}
+
+// types of suggestions besides edu centers and courses
+const (
+	SuggestionTypeTeacher  = "teacher"
+	SuggestionTypeDistrict = "district"
+)
+
+const DefaultSuggestLimit = 5
+
+// SuggestQuery is the type-ahead of search box, Limit is the number of suggestions of each type
+type SuggestQuery struct {
+	Prefix string `form:"prefix" validate:"required,max=100"`
+	Limit  int    `form:"limit" validate:"omitempty,gte=1,lte=10"`
+}
+
+// Suggestion is the completion of prefix. ID is set for edu centers and courses.
+// Match is 0 when text starts with prefix and 1 when one of its other words does.
+type Suggestion struct {
+	Type  string     `json:"type" db:"type"`
+	Text  string     `json:"text" db:"text"`
+	ID    *uuid.UUID `json:"id,omitempty" db:"id"`
+	Match int        `json:"-" db:"match"`
+}
+
+type Suggestions struct {
+	Suggestions []Suggestion `json:"suggestions"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken is the record of issued refresh token. Token is rotated when it is exchanged for new tokens
+// and revoked when its session is terminated.
+type RefreshToken struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	SessionID uuid.UUID  `json:"session_id" db:"session_id"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	IssuedAt  time.Time  `json:"issued_at" db:"issued_at"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	RotatedAt *time.Time `json:"rotated_at" db:"rotated_at"`
+	RevokedAt *time.Time `json:"revoked_at" db:"revoked_at"`
+}
+
+// Session is the login of user on a device, it lasts while its refresh tokens are rotated in time
+// and isn't terminated. Current marks session of the request.
+type Session struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	UserID       uuid.UUID  `json:"-" db:"user_id"`
+	Device       string     `json:"device" db:"device"`
+	IP           string     `json:"ip" db:"ip"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	LastSeenAt   time.Time  `json:"last_seen_at" db:"last_seen_at"`
+	ExpiresAt    time.Time  `json:"expires_at" db:"expires_at"`
+	TerminatedAt *time.Time `json:"-" db:"terminated_at"`
+	Current      bool       `json:"current" db:"-"`
+}
+
+type Sessions struct {
+	Sessions []Session `json:"sessions"`
+}
+
+// Client is the device and address tokens are issued to
+type Client struct {
+	Device string
+	IP     string
+}
+
+// RefreshTokenDto is the refresh token sent by client in JSON or form body
+type RefreshTokenDto struct {
+	RefreshToken string `json:"refresh_token" form:"refresh_token"`
+}
+
+// AccessClaims are the claims of validated access token
+type AccessClaims struct {
+	UserID    uuid.UUID
+	Role      Role
+	SessionID uuid.UUID
+}
+
+// RefreshClaims are the claims of validated refresh token
+type RefreshClaims struct {
+	TokenID uuid.UUID
+	UserID  uuid.UUID
+}
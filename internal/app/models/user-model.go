@@ -14,17 +14,25 @@ const (
 	UserRole  Role = "User"
 )
 
+// Requester is the authenticated user performing the request
+type Requester struct {
+	UserID uuid.UUID
+	Role   Role
+}
+
 type User struct {
 	ID        uuid.UUID `json:"id" db:"id"`
 	FirstName string    `json:"first_name" db:"first_name"`
 	LastName  string    `json:"last_name" db:"last_name"`
 	Email     string    `json:"email" db:"email" validate:"required,email"`
-	Username  string    `json:"username" db:"username"`
-	Password  string    `json:"password" db:"password" validate:"required,min=8,max=16"`
-	Role      Role      `json:"role" db:"role"`
-	Avatar    string    `json:"avatar" db:"avatar"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	// EmailVerified is true when user opened verification link mailed to the current email
+	EmailVerified bool      `json:"email_verified" db:"email_verified"`
+	Username      string    `json:"username" db:"username"`
+	Password      string    `json:"password" db:"password" validate:"required,min=8,max=16"`
+	Role          Role      `json:"role" db:"role"`
+	Avatar        string    `json:"avatar" db:"avatar"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
 }
 
 type UpdateUserDto struct {
@@ -38,3 +46,9 @@ type UpdateUserDto struct {
 	OldAvatar string                `form:"old_avatar"`
 	UpdatedAt time.Time             `json:"updated_at" db:"updated_at"`
 }
+
+// EmailVerificationClaims are the claims of validated email verification token
+type EmailVerificationClaims struct {
+	UserID uuid.UUID
+	Email  string
+}
@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	custom_errors "edumatch/internal/app/errors"
 	"edumatch/internal/app/models"
+	database "edumatch/pkg/db"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,47 +14,69 @@ import (
 )
 
 type CourseRepositoryInterface interface {
-	GetAllCourses() (models.AllCourses, error)
+	GetAllCourses(params models.CourseQuery) (models.AllCourses, error)
 	CreateCourse(course models.CreateCourseDto) (models.Course, error)
 	GetCourse(courseID uuid.UUID) (models.Course, error)
 	UpdateCourse(newCourse models.UpdateCourseDto) (models.Course, error)
 	DeleteCourse(courseID uuid.UUID) error
-	GiveRating(rating models.CourseRating) error
+	GiveRating(tx database.Transaction, rating models.CourseRating) (models.CourseRating, error)
+	GetUserRating(courseID uuid.UUID, userID uuid.UUID) (models.CourseRating, error)
+	DeleteRating(tx database.Transaction, courseID uuid.UUID, userID uuid.UUID) error
+	GetEduCenterCourses(eduCenterID uuid.UUID, params models.CourseQuery) (models.AllCourses, error)
+	GetCourseSummary(eduCenterID uuid.UUID, topLimit int) (models.CourseSummary, error)
+	GetCourseOwner(courseID uuid.UUID) (uuid.UUID, error)
+	GetEduCenterOwner(eduCenterID uuid.UUID) (uuid.UUID, error)
+	GetCourseDiscounts(courseID uuid.UUID) ([]models.CourseDiscount, error)
+	CreateDiscount(discount models.CourseDiscountDto) (models.CourseDiscount, error)
+	DeleteDiscount(courseID uuid.UUID, discountID uuid.UUID) error
+	GetCourseTimetable(courseID uuid.UUID) (models.Timetable, error)
+	GetEduCenterTimetable(eduCenterID uuid.UUID) (models.Timetable, error)
+	BeginTransaction() (database.Transaction, error)
 }
 
 type CourseRepository struct {
-	db *sqlx.DB
+	db          *sqlx.DB
+	ratingPrior ratingPrior
 }
 
 func NewCourseRepository(db *sqlx.DB) CourseRepositoryInterface {
 	return &CourseRepository{
-		db: db,
+		db:          db,
+		ratingPrior: loadRatingPrior(),
 	}
 }
 
 func (r *CourseRepository) CreateCourse(course models.CreateCourseDto) (models.Course, error) {
 	var (
-		newCourse models.Course
-		query     = `INSERT INTO courses (name, description, teacher, edu_center_id) VALUES ($1, $2, $3, $4) RETURNING id,name,description,teacher,edu_center_id,created_at`
+		courseID uuid.UUID
+		query    = `INSERT INTO courses (name, description, teacher, edu_center_id, price, currency, price_unit)
+		SELECT $1, $2, $3, e.id, $5, $6, $7 FROM edu_centers e WHERE e.id = $4 AND e.deleted_at IS NULL
+		RETURNING id`
 	)
 
-	err := r.db.Get(&newCourse, query, course.Name, course.Description, course.Teacher, course.EduCenterID)
+	amount, currency, unit := priceValues(course.Price)
+	err := r.db.Get(&courseID, query, course.Name, course.Description, course.Teacher, course.EduCenterID, amount, currency, unit)
 	if err != nil {
+		//course can be added only to existing center
+		if err == sql.ErrNoRows {
+			err = custom_errors.ErrEduCenterNotFound
+		}
 		return models.Course{}, err
 	}
 
-	return newCourse, nil
+	return r.GetCourse(courseID)
 }
 
 func (r *CourseRepository) GetCourse(courseID uuid.UUID) (models.Course, error) {
 	var (
 		course models.Course
-		query  = `SELECT c.id,c.name,c.description,c.teacher,c.edu_center_id,c.updated_at,c.created_at, COALESCE(ROUND(AVG(score),1),0) AS rating FROM courses c
-		LEFT JOIN ratings r ON c.id = r.course_id WHERE c.id = $1 AND c.deleted_at IS NULL 
-		GROUP BY c.id, c.name, c.description, c.teacher, c.edu_center_id, c.created_at, c.updated_at`
+		price  coursePriceRow
+		query  = fmt.Sprintf(`SELECT c.id,c.name,c.description,c.teacher,c.edu_center_id,c.updated_at,c.created_at, %s, %s FROM courses c
+		%s
+		WHERE c.id = $1 AND c.deleted_at IS NULL`, r.ratingPrior.ratingStatsSQL(models.CourseTarget, "c"), coursePriceSQL, coursePriceJoin)
 	)
 
-	if err := r.db.QueryRow(query, courseID).Scan(
+	if err := r.db.QueryRow(query, courseID).Scan(append([]interface{}{
 		&course.ID,
 		&course.Name,
 		&course.Description,
@@ -61,41 +85,123 @@ func (r *CourseRepository) GetCourse(courseID uuid.UUID) (models.Course, error)
 		&course.CreatedAt,
 		&course.UpdatedAt,
 		&course.Rating,
-	); err != nil {
+		&course.RatingCount,
+		&course.WeightedRating,
+		&course.Distribution.Score1,
+		&course.Distribution.Score2,
+		&course.Distribution.Score3,
+		&course.Distribution.Score4,
+		&course.Distribution.Score5,
+	}, price.dest()...)...); err != nil {
 		if err == sql.ErrNoRows {
 			err = custom_errors.ErrCourseNotFound
 		}
 		return models.Course{}, err
 	}
+	course.Price = price.price(course.ID)
 
 	return course, nil
 }
 
-func (r *CourseRepository) GetAllCourses() (models.AllCourses, error) {
-	var allCourses models.AllCourses
+// courseSortColumns maps sort keys of listing to sort expression and its sql type
+var courseSortColumns = map[string][2]string{
+	models.CourseSortRating:    {"weighted_rating", "numeric"},
+	models.CourseSortCreatedAt: {"created_at", "timestamptz"},
+	models.CourseSortPrice:     {"effective_price", "numeric"},
+}
 
-	query := `
-		WITH courses_with_ratings AS (
+func (r *CourseRepository) GetAllCourses(params models.CourseQuery) (models.AllCourses, error) {
+	var (
+		args            queryArgs
+		innerConditions = []string{"c.deleted_at IS NULL", "c.hidden_at IS NULL"}
+		outerConditions []string
+	)
+
+	if params.EduCenterID != "" {
+		innerConditions = append(innerConditions, fmt.Sprintf("c.edu_center_id = %s", args.add(params.EduCenterID)))
+	}
+	if params.Teacher != "" {
+		innerConditions = append(innerConditions, fmt.Sprintf("c.teacher ILIKE '%%' || %s || '%%'", args.add(escapeLike(params.Teacher))+"::text"))
+	}
+	if params.Text != "" {
+		text := args.add(escapeLike(params.Text)) + "::text"
+		innerConditions = append(innerConditions, fmt.Sprintf("(c.name ILIKE '%%' || %[1]s || '%%' OR c.description ILIKE '%%' || %[1]s || '%%')", text))
+	}
+	if params.Day != nil || params.TimeFrom != "" || params.TimeTo != "" {
+		innerConditions = append(innerConditions, scheduleCondition(&args, params))
+	}
+	if params.MinRating > 0 {
+		outerConditions = append(outerConditions, fmt.Sprintf("rating >= %s", args.add(params.MinRating)))
+	}
+	if params.MaxRating > 0 {
+		outerConditions = append(outerConditions, fmt.Sprintf("rating <= %s", args.add(params.MaxRating)))
+	}
+	//prices are compared only in the same currency and unit, courses without price are left out when sorting by price
+	if params.Currency != "" {
+		innerConditions = append(innerConditions, fmt.Sprintf("c.currency = %s", args.add(params.Currency)))
+	}
+	if params.PriceUnit != "" {
+		innerConditions = append(innerConditions, fmt.Sprintf("c.price_unit = %s", args.add(params.PriceUnit)))
+	}
+	if params.MinPrice > 0 {
+		outerConditions = append(outerConditions, fmt.Sprintf("effective_price >= %s", args.add(params.MinPrice)))
+	}
+	if params.MaxPrice > 0 {
+		outerConditions = append(outerConditions, fmt.Sprintf("effective_price <= %s", args.add(params.MaxPrice)))
+	}
+	if params.SortBy == models.CourseSortPrice {
+		outerConditions = append(outerConditions, "effective_price IS NOT NULL")
+	}
+
+	cte := fmt.Sprintf(`WITH courses_with_ratings AS (
 			SELECT c.id, c.name, c.description, c.teacher, c.edu_center_id,
-			c.created_at, c.updated_at, COALESCE(ROUND(AVG(r.score), 1), 0) AS rating
+			c.created_at, c.updated_at, %s, %s
 			FROM courses c
-			LEFT JOIN ratings r ON c.id = r.course_id
-			WHERE c.deleted_at IS NULL
-			GROUP BY c.id
-		)
-		SELECT *,COUNT(*) OVER() AS count FROM courses_with_ratings;
-	`
+			%s
+			WHERE %s
+		)`, r.ratingPrior.ratingStatsSQL(models.CourseTarget, "c"), coursePriceSQL, coursePriceJoin,
+		joinConditions(innerConditions))
+
+	//total count of filtered courses
+	var allCourses models.AllCourses
+	countQuery := fmt.Sprintf(`%s SELECT COUNT(*) FROM courses_with_ratings WHERE %s`, cte, joinConditions(outerConditions))
+	if err := r.db.Get(&allCourses.Count, countQuery, args.values...); err != nil {
+		return models.AllCourses{}, err
+	}
+
+	sortColumn := courseSortColumns[params.SortBy]
+	if params.Cursor != "" {
+		condition, err := keysetCondition(&args, params.Cursor, sortColumn[0], sortColumn[1], params.Order)
+		if err != nil {
+			return models.AllCourses{}, err
+		}
+		outerConditions = append(outerConditions, condition)
+	}
 
-	rows, err := r.db.Query(query)
+	// one more row is fetched to know whether next page exists
+	query := fmt.Sprintf(`%s
+		SELECT id, name, description, teacher, edu_center_id, created_at, updated_at, %s, %s, (%s)::text AS sort_value
+		FROM courses_with_ratings
+		WHERE %s
+		ORDER BY %s %s, id %s
+		LIMIT %s OFFSET %s`,
+		cte, ratingStatsColumns, coursePriceColumns, sortColumn[0], joinConditions(outerConditions), sortColumn[0], params.Order, params.Order,
+		args.add(params.Limit+1), args.add(pageOffset(params.PageQuery)))
+
+	rows, err := r.db.Query(query, args.values...)
 	if err != nil {
 		return models.AllCourses{}, err
 	}
 
 	defer rows.Close()
 
+	var sortValue, lastSortValue string
 	for rows.Next() {
-		var course models.Course
-		scanErr := rows.Scan(
+		var (
+			course models.Course
+			price  coursePriceRow
+		)
+		scanErr := rows.Scan(append(append([]interface{}{
 			&course.ID,
 			&course.Name,
 			&course.Description,
@@ -104,12 +210,26 @@ func (r *CourseRepository) GetAllCourses() (models.AllCourses, error) {
 			&course.CreatedAt,
 			&course.UpdatedAt,
 			&course.Rating,
-			&allCourses.Count,
-		)
+			&course.RatingCount,
+			&course.WeightedRating,
+			&course.Distribution.Score1,
+			&course.Distribution.Score2,
+			&course.Distribution.Score3,
+			&course.Distribution.Score4,
+			&course.Distribution.Score5,
+		}, price.dest()...), &sortValue)...)
 		if scanErr != nil {
 			return models.AllCourses{}, scanErr
 		}
+		course.Price = price.price(course.ID)
 
+		if len(allCourses.Courses) == params.Limit {
+			//there is next page
+			last := allCourses.Courses[params.Limit-1]
+			allCourses.NextCursor = encodeCursor(lastSortValue, last.ID)
+			break
+		}
+		lastSortValue = sortValue
 		allCourses.Courses = append(allCourses.Courses, course)
 	}
 
@@ -124,10 +244,13 @@ func (r *CourseRepository) GetAllCourses() (models.AllCourses, error) {
 func (r *CourseRepository) UpdateCourse(course models.UpdateCourseDto) (models.Course, error) {
 	course.UpdatedAt = time.Now().UTC()
 	var (
-		updatedCourse models.Course
-		query         = `UPDATE courses SET name=$2,description=$3,teacher=$4,edu_center_id=$5,updated_at=$6 WHERE id=$1 AND deleted_at IS NULL RETURNING id, name, description, teacher, edu_center_id,(SELECT COALESCE(ROUND(AVG(score),1),0) FROM ratings WHERE course_id =$1) AS rating,created_at,updated_at`
+		courseID uuid.UUID
+		query    = `UPDATE courses SET name=$2,description=$3,teacher=$4,edu_center_id=$5,updated_at=$6,price=$7,currency=$8,price_unit=$9
+		WHERE id=$1 AND deleted_at IS NULL RETURNING id`
 	)
-	if err := r.db.Get(&updatedCourse, query, course.ID, course.Name, course.Description, course.Teacher, course.EduCenterID, course.UpdatedAt); err != nil {
+	amount, currency, unit := priceValues(course.Price)
+	if err := r.db.Get(&courseID, query, course.ID, course.Name, course.Description, course.Teacher, course.EduCenterID, course.UpdatedAt,
+		amount, currency, unit); err != nil {
 		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
 			return models.Course{}, custom_errors.ErrCourseExists
 		}
@@ -138,7 +261,7 @@ func (r *CourseRepository) UpdateCourse(course models.UpdateCourseDto) (models.C
 		return models.Course{}, err
 	}
 
-	return updatedCourse, nil
+	return r.GetCourse(courseID)
 }
 
 func (r *CourseRepository) DeleteCourse(courseID uuid.UUID) error {
@@ -150,15 +273,364 @@ func (r *CourseRepository) DeleteCourse(courseID uuid.UUID) error {
 	return nil
 }
 
-func (r *CourseRepository) GiveRating(rating models.CourseRating) error {
+// GiveRating creates rating of user or changes score of existing one and updates rating aggregates of course
+func (r *CourseRepository) GiveRating(tx database.Transaction, rating models.CourseRating) (models.CourseRating, error) {
+	var (
+		savedRating models.CourseRating
+		query       = `INSERT INTO ratings (score, owner_id, course_id) VALUES ($1,$2,$3)
+		ON CONFLICT (owner_id, course_id) WHERE course_id IS NOT NULL
+		DO UPDATE SET score = EXCLUDED.score, updated_at = CURRENT_TIMESTAMP
+		RETURNING id,score,owner_id,course_id,created_at,updated_at`
+	)
+
+	if err := lockRatingTarget(tx, models.CourseTarget, rating.CourseID); err != nil {
+		return models.CourseRating{}, err
+	}
+
+	if err := tx.Get(&savedRating, query, rating.Score, rating.OwnerID, rating.CourseID); err != nil {
+		return models.CourseRating{}, err
+	}
+
+	if err := refreshRatingAggregates(tx, models.CourseTarget, rating.CourseID); err != nil {
+		return models.CourseRating{}, err
+	}
+
+	return savedRating, nil
+}
+
+func (r *CourseRepository) GetUserRating(courseID uuid.UUID, userID uuid.UUID) (models.CourseRating, error) {
 	var (
-		query = `INSERT INTO ratings (score, owner_id, course_id) VALUES ($1,$2,$3) RETURNING score,owner_id,course_id`
+		rating models.CourseRating
+		query  = `SELECT id,score,owner_id,course_id,created_at,updated_at FROM ratings WHERE course_id = $1 AND owner_id = $2`
 	)
+	if err := r.db.Get(&rating, query, courseID, userID); err != nil {
+		if err == sql.ErrNoRows {
+			err = custom_errors.ErrRatingNotFound
+		}
+		return models.CourseRating{}, err
+	}
 
-	_, err := r.db.Exec(query, rating.Score, rating.OwnerID, rating.CourseID)
+	return rating, nil
+}
+
+func (r *CourseRepository) DeleteRating(tx database.Transaction, courseID uuid.UUID, userID uuid.UUID) error {
+	if err := lockRatingTarget(tx, models.CourseTarget, courseID); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`DELETE FROM ratings WHERE course_id = $1 AND owner_id = $2`, courseID, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if affected == 0 {
+		return custom_errors.ErrRatingNotFound
+	}
+
+	return refreshRatingAggregates(tx, models.CourseTarget, courseID)
+}
+
+func (r *CourseRepository) GetEduCenterCourses(eduCenterID uuid.UUID, params models.CourseQuery) (models.AllCourses, error) {
+	var exists bool
+	if err := r.db.Get(&exists, `SELECT EXISTS(SELECT 1 FROM edu_centers WHERE id = $1 AND deleted_at IS NULL)`, eduCenterID); err != nil {
+		return models.AllCourses{}, err
+	}
+	if !exists {
+		return models.AllCourses{}, custom_errors.ErrEduCenterNotFound
+	}
+
+	params.EduCenterID = eduCenterID.String()
+	return r.GetAllCourses(params)
+}
+
+func (r *CourseRepository) GetCourseSummary(eduCenterID uuid.UUID, topLimit int) (models.CourseSummary, error) {
+	var summary models.CourseSummary
+	query := fmt.Sprintf(`SELECT c.id, c.name, c.description, c.teacher, c.edu_center_id, c.created_at, c.updated_at,
+	%s, %s, COUNT(*) OVER() AS count
+	FROM courses c
+	%s
+	WHERE c.edu_center_id = $1 AND c.deleted_at IS NULL AND c.hidden_at IS NULL
+	ORDER BY weighted_rating DESC, c.id
+	LIMIT $2`, r.ratingPrior.ratingStatsSQL(models.CourseTarget, "c"), coursePriceSQL, coursePriceJoin)
+
+	rows, err := r.db.Query(query, eduCenterID, topLimit)
+	if err != nil {
+		return models.CourseSummary{}, err
+	}
+	defer rows.Close()
 
+	//count is taken over all courses of center before limit
+	summary.TopRated = []models.Course{}
+	for rows.Next() {
+		var (
+			course models.Course
+			price  coursePriceRow
+		)
+		scanErr := rows.Scan(append(append([]interface{}{
+			&course.ID,
+			&course.Name,
+			&course.Description,
+			&course.Teacher,
+			&course.EduCenterID,
+			&course.CreatedAt,
+			&course.UpdatedAt,
+			&course.Rating,
+			&course.RatingCount,
+			&course.WeightedRating,
+			&course.Distribution.Score1,
+			&course.Distribution.Score2,
+			&course.Distribution.Score3,
+			&course.Distribution.Score4,
+			&course.Distribution.Score5,
+		}, price.dest()...), &summary.Count)...)
+		if scanErr != nil {
+			return models.CourseSummary{}, scanErr
+		}
+		course.Price = price.price(course.ID)
+		summary.TopRated = append(summary.TopRated, course)
+	}
+
+	if err := rows.Err(); err != nil {
+		return models.CourseSummary{}, err
+	}
+
+	return summary, nil
+}
+
+// GetCourseOwner returns owner of edu center the course belongs to
+func (r *CourseRepository) GetCourseOwner(courseID uuid.UUID) (uuid.UUID, error) {
+	var ownerID uuid.UUID
+	query := `SELECT e.owner_id FROM courses c
+	JOIN edu_centers e ON e.id = c.edu_center_id
+	WHERE c.id = $1 AND c.deleted_at IS NULL`
+	if err := r.db.Get(&ownerID, query, courseID); err != nil {
+		if err == sql.ErrNoRows {
+			err = custom_errors.ErrCourseNotFound
+		}
+		return uuid.Nil, err
+	}
+	return ownerID, nil
+}
+
+func (r *CourseRepository) GetEduCenterOwner(eduCenterID uuid.UUID) (uuid.UUID, error) {
+	var ownerID uuid.UUID
+	err := r.db.Get(&ownerID, `SELECT owner_id FROM edu_centers WHERE id = $1 AND deleted_at IS NULL`, eduCenterID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = custom_errors.ErrEduCenterNotFound
+		}
+		return uuid.Nil, err
+	}
+	return ownerID, nil
+}
+
+// GetCourseDiscounts returns running and upcoming discount campaigns of course
+func (r *CourseRepository) GetCourseDiscounts(courseID uuid.UUID) ([]models.CourseDiscount, error) {
+	var exists bool
+	if err := r.db.Get(&exists, `SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1 AND deleted_at IS NULL)`, courseID); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, custom_errors.ErrCourseNotFound
+	}
+
+	discounts := []models.CourseDiscount{}
+	query := `SELECT id, course_id, name, percent, starts_at, ends_at, created_at FROM course_discounts
+	WHERE course_id = $1 AND ends_at > CURRENT_TIMESTAMP
+	ORDER BY starts_at, id`
+	if err := r.db.Select(&discounts, query, courseID); err != nil {
+		return nil, err
+	}
+	return discounts, nil
+}
+
+func (r *CourseRepository) CreateDiscount(discount models.CourseDiscountDto) (models.CourseDiscount, error) {
+	var (
+		newDiscount models.CourseDiscount
+		query       = `INSERT INTO course_discounts (course_id, name, percent, starts_at, ends_at)
+		SELECT c.id, $2, $3, $4, $5 FROM courses c WHERE c.id = $1 AND c.deleted_at IS NULL
+		RETURNING id, course_id, name, percent, starts_at, ends_at, created_at`
+	)
+
+	err := r.db.Get(&newDiscount, query, discount.CourseID, discount.Name, discount.Percent, discount.StartsAt, discount.EndsAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = custom_errors.ErrCourseNotFound
+		}
+		return models.CourseDiscount{}, err
+	}
+	return newDiscount, nil
+}
+
+func (r *CourseRepository) DeleteDiscount(courseID uuid.UUID, discountID uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM course_discounts WHERE id = $1 AND course_id = $2`, discountID, courseID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return custom_errors.ErrDiscountNotFound
+	}
 	return nil
 }
+
+// GetCourseTimetable returns slots of all groups of visible course
+func (r *CourseRepository) GetCourseTimetable(courseID uuid.UUID) (models.Timetable, error) {
+	var timetable models.Timetable
+	if err := r.db.Get(&timetable.Name, `SELECT name FROM courses WHERE id = $1 AND deleted_at IS NULL AND hidden_at IS NULL`, courseID); err != nil {
+		if err == sql.ErrNoRows {
+			err = custom_errors.ErrCourseNotFound
+		}
+		return models.Timetable{}, err
+	}
+
+	slots, err := r.getTimetableSlots("c.id = $1", courseID)
+	if err != nil {
+		return models.Timetable{}, err
+	}
+	timetable.Slots = slots
+	return timetable, nil
+}
+
+// GetEduCenterTimetable returns slots of all groups of visible courses of edu center
+func (r *CourseRepository) GetEduCenterTimetable(eduCenterID uuid.UUID) (models.Timetable, error) {
+	var timetable models.Timetable
+	if err := r.db.Get(&timetable.Name, `SELECT name FROM edu_centers WHERE id = $1 AND deleted_at IS NULL AND hidden_at IS NULL`, eduCenterID); err != nil {
+		if err == sql.ErrNoRows {
+			err = custom_errors.ErrEduCenterNotFound
+		}
+		return models.Timetable{}, err
+	}
+
+	slots, err := r.getTimetableSlots("e.id = $1", eduCenterID)
+	if err != nil {
+		return models.Timetable{}, err
+	}
+	timetable.Slots = slots
+	return timetable, nil
+}
+
+// getTimetableSlots returns slots of groups of visible courses matching condition. Updated time of slot is the latest
+// change of its group, course or center, as any of them changes the calendar event.
+func (r *CourseRepository) getTimetableSlots(condition string, args ...interface{}) ([]models.TimetableSlot, error) {
+	query := fmt.Sprintf(`SELECT g.id AS schedule_id, g.name AS schedule_name, c.name AS course_name, e.name AS edu_center_name,
+	COALESCE(e.address, '') AS address, g.room, COALESCE(NULLIF(g.teacher, ''), c.teacher, '') AS teacher, g.language,
+	to_char(g.start_date, 'YYYY-MM-DD') AS start_date, COALESCE(to_char(g.end_date, 'YYYY-MM-DD'), '') AS end_date,
+	s.position, s.day_of_week, to_char(s.start_time, 'HH24:MI') AS start_time, to_char(s.end_time, 'HH24:MI') AS end_time,
+	GREATEST(g.updated_at, c.updated_at, e.updated_at) AS updated_at
+	FROM course_schedules g
+	JOIN schedule_slots s ON s.schedule_id = g.id
+	JOIN courses c ON c.id = g.course_id
+	JOIN edu_centers e ON e.id = c.edu_center_id
+	WHERE %s AND c.deleted_at IS NULL AND c.hidden_at IS NULL
+	ORDER BY g.start_date, c.name, s.day_of_week, s.start_time, g.id`, condition)
+
+	slots := []models.TimetableSlot{}
+	if err := r.db.Select(&slots, query, args...); err != nil {
+		return nil, err
+	}
+	return slots, nil
+}
+
+func (r *CourseRepository) BeginTransaction() (database.Transaction, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	return &database.CustomTx{Tx: tx}, nil
+}
+
+// scheduleCondition matches courses having a slot of running or upcoming group on given day and within given time range
+func scheduleCondition(args *queryArgs, params models.CourseQuery) string {
+	slotConditions := []string{"g.course_id = c.id", "(g.end_date IS NULL OR g.end_date >= CURRENT_DATE)"}
+	if params.Day != nil {
+		slotConditions = append(slotConditions, fmt.Sprintf("s.day_of_week = %s", args.add(*params.Day)))
+	}
+	if params.TimeFrom != "" {
+		slotConditions = append(slotConditions, fmt.Sprintf("s.start_time >= %s::time", args.add(params.TimeFrom)))
+	}
+	if params.TimeTo != "" {
+		slotConditions = append(slotConditions, fmt.Sprintf("s.end_time <= %s::time", args.add(params.TimeTo)))
+	}
+	return fmt.Sprintf(`EXISTS (SELECT 1 FROM course_schedules g JOIN schedule_slots s ON s.schedule_id = g.id WHERE %s)`,
+		joinConditions(slotConditions))
+}
+
+// coursePriceSQL selects price of course, the best discount joined by coursePriceJoin and the discounted price
+const coursePriceSQL = `c.price, c.currency, c.price_unit, d.id AS discount_id, d.name AS discount_name, d.percent AS discount_percent,
+	d.starts_at AS discount_starts_at, d.ends_at AS discount_ends_at, d.created_at AS discount_created_at,
+	` + effectivePriceSQL + ` AS effective_price`
+
+// coursePriceColumns are the columns selected by coursePriceSQL
+const coursePriceColumns = `price, currency, price_unit, discount_id, discount_name, discount_percent,
+	discount_starts_at, discount_ends_at, discount_created_at, effective_price`
+
+// coursePriceJoin joins the biggest discount of course running now
+const coursePriceJoin = `LEFT JOIN LATERAL (
+	SELECT id, name, percent, starts_at, ends_at, created_at FROM course_discounts
+	WHERE course_id = c.id AND starts_at <= CURRENT_TIMESTAMP AND ends_at > CURRENT_TIMESTAMP
+	ORDER BY percent DESC, ends_at, id
+	LIMIT 1
+) d ON TRUE`
+
+// effectivePriceSQL is the discounted price, it is computed only here so responses agree with price filters and sorting
+const effectivePriceSQL = `ROUND(c.price * (100 - COALESCE(d.percent, 0)) / 100, 2)`
+
+// coursePriceRow receives nullable price columns of course
+type coursePriceRow struct {
+	amount            sql.NullFloat64
+	currency          sql.NullString
+	unit              sql.NullString
+	discountID        uuid.NullUUID
+	discountName      sql.NullString
+	discountPercent   sql.NullFloat64
+	discountStartsAt  sql.NullTime
+	discountEndsAt    sql.NullTime
+	discountCreatedAt sql.NullTime
+	effective         sql.NullFloat64
+}
+
+// dest returns scan destinations in order of coursePriceColumns
+func (p *coursePriceRow) dest() []interface{} {
+	return []interface{}{&p.amount, &p.currency, &p.unit, &p.discountID, &p.discountName, &p.discountPercent,
+		&p.discountStartsAt, &p.discountEndsAt, &p.discountCreatedAt, &p.effective}
+}
+
+// price returns nil for courses without price
+func (p *coursePriceRow) price(courseID uuid.UUID) *models.CoursePrice {
+	if !p.amount.Valid {
+		return nil
+	}
+	price := &models.CoursePrice{
+		Amount:    p.amount.Float64,
+		Currency:  p.currency.String,
+		Unit:      p.unit.String,
+		Effective: p.effective.Float64,
+	}
+	if p.discountID.Valid {
+		price.Discount = &models.CourseDiscount{
+			ID:        p.discountID.UUID,
+			CourseID:  courseID,
+			Name:      p.discountName.String,
+			Percent:   p.discountPercent.Float64,
+			StartsAt:  nullTime(p.discountStartsAt),
+			EndsAt:    nullTime(p.discountEndsAt),
+			CreatedAt: nullTime(p.discountCreatedAt),
+		}
+	}
+	return price
+}
+
+// priceValues returns price column values, all of them are NULL for courses without price
+func priceValues(price *models.CoursePriceDto) (interface{}, interface{}, interface{}) {
+	if price == nil {
+		return nil, nil, nil
+	}
+	return price.Amount, price.Currency, price.Unit
+}
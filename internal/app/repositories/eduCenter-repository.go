@@ -11,55 +11,125 @@ import (
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
-	"github.com/spf13/cast"
 )
 
 type EduCenterRepositoryInterface interface {
-	GetAllEduCenters() (models.AllEduCenters, error)
+	GetAllEduCenters(params models.EduCenterQuery) (models.AllEduCenters, error)
 	CreateEduCenter(tx database.Transaction, eduCenter models.CreateEduCenterDto) (models.EduCenter, error)
 	GetEduCenter(eduCenterID uuid.UUID) (models.EduCenter, error)
 	UpdateEduCenter(tx database.Transaction, eduCenter models.UpdateEduCenterDto) (models.EduCenter, error)
 	DeleteEduCenter(eduCenterID uuid.UUID) error
-	GiveRating(rating models.EduCenterRating) error
+	GiveRating(tx database.Transaction, rating models.EduCenterRating) (models.EduCenterRating, error)
+	GetUserRating(eduCenterID uuid.UUID, userID uuid.UUID) (models.EduCenterRating, error)
+	DeleteRating(tx database.Transaction, eduCenterID uuid.UUID, userID uuid.UUID) error
 	BeginTransaction() (database.Transaction, error)
 	AddContacts(tx database.Transaction, eduCenterID uuid.UUID, contacts models.Contact) (models.Contact, error)
 	UpdateContacts(tx database.Transaction, contacts models.Contact, eduCenterID uuid.UUID) (models.Contact, error)
-	GetEduCenterByLocation(location models.NearEduCenterDto) ([]models.NearEduCenter, error)
+	GetEduCenterByLocation(location models.NearEduCenterDto) (models.AllNearEduCenters, error)
+	GetEduCenterOwner(eduCenterID uuid.UUID) (uuid.UUID, error)
+	GetEduCenterMap(params models.EduCenterMapQuery, bbox models.BoundingBox, cellSize float64, cellsLimit int) ([]models.MapCluster, error)
 }
 type EduCenterRepository struct {
-	db *sqlx.DB
+	db          *sqlx.DB
+	ratingPrior ratingPrior
 }
 
 func NewEduCenterRepository(db *sqlx.DB) EduCenterRepositoryInterface {
 	return &EduCenterRepository{
-		db: db,
+		db:          db,
+		ratingPrior: loadRatingPrior(),
 	}
 }
 
-func (r *EduCenterRepository) GetAllEduCenters() (models.AllEduCenters, error) {
-	var allEduCenters models.AllEduCenters
-	query := `WITH edu_centers_with_rating_with_contacts AS (
+// eduCenterSortColumns maps sort keys of listing to sort expression and its sql type
+var eduCenterSortColumns = map[string][2]string{
+	models.EduCenterSortRating:    {"weighted_rating", "numeric"},
+	models.EduCenterSortName:      {"COALESCE(name, '')", "text"},
+	models.EduCenterSortCreatedAt: {"created_at", "timestamptz"},
+	models.EduCenterSortDistance:  {"distance", "float8"},
+}
+
+// eduCenterFilterConditions returns conditions of visible centers e with contacts c matching filter
+func eduCenterFilterConditions(args *queryArgs, filter models.EduCenterFilter) []string {
+	conditions := []string{"e.deleted_at IS NULL", "e.hidden_at IS NULL"}
+	if filter.NamePrefix != "" {
+		conditions = append(conditions, fmt.Sprintf("e.name ILIKE %s || '%%'", args.add(escapeLike(filter.NamePrefix))+"::text"))
+	}
+	if !filter.CreatedAfter.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("e.created_at >= %s", args.add(filter.CreatedAfter)))
+	}
+	if filter.HasContacts != nil {
+		hasContacts := `(COALESCE(c.instagram, '') <> '' OR COALESCE(c.telegram, '') <> '' OR COALESCE(c.website, '') <> '' OR COALESCE(c.phone_number, '') <> '')`
+		if !*filter.HasContacts {
+			hasContacts = "NOT " + hasContacts
+		}
+		conditions = append(conditions, hasContacts)
+	}
+	if filter.MinRating > 0 {
+		conditions = append(conditions, fmt.Sprintf("e.rating_avg >= %s", args.add(filter.MinRating)))
+	}
+	return conditions
+}
+
+func (r *EduCenterRepository) GetAllEduCenters(params models.EduCenterQuery) (models.AllEduCenters, error) {
+	var (
+		args            queryArgs
+		outerConditions []string
+		distance        = "NULL::float8"
+	)
+
+	if params.Latitude != nil && params.Longitude != nil {
+		distance = distanceSQL("e.location", args.add(*params.Latitude)+"::float8", args.add(*params.Longitude)+"::float8")
+	}
+	innerConditions := eduCenterFilterConditions(&args, params.EduCenterFilter)
+
+	cte := fmt.Sprintf(`WITH edu_centers_with_rating_with_contacts AS (
 		SELECT e.id, e.name, e.html_description, e.address, e.location, e.owner_id, e.cover_image, e.created_at, e.updated_at,
-		COALESCE(ROUND(AVG(r.score), 1), 0) AS rating,
+		%s,
 		COALESCE(c.instagram, 'default_instagram_value') AS instagram,
 		COALESCE(c.telegram, 'default_telegram_value') AS telegram,
 		COALESCE(c.phone_number, 'default_phone_number_value') AS phone_number,
-		COALESCE(c.website, 'default_website_value') AS website
+		COALESCE(c.website, 'default_website_value') AS website,
+		%s AS distance
 		FROM edu_centers e
-		LEFT JOIN ratings r ON e.id = r.edu_center_id
 		LEFT JOIN contacts c ON e.id = c.edu_center_id
-		WHERE e.deleted_at IS NULL
-		GROUP BY e.id, c.instagram, c.telegram, c.phone_number, c.website
-	)
-	SELECT *, COUNT(*) OVER () as count FROM edu_centers_with_rating_with_contacts;
-	`
+		WHERE %s
+	)`, r.ratingPrior.ratingStatsSQL(models.EduCenterTarget, "e"), distance, joinConditions(innerConditions))
+
+	//total count of filtered centers
+	var allEduCenters models.AllEduCenters
+	countQuery := fmt.Sprintf(`%s SELECT COUNT(*) FROM edu_centers_with_rating_with_contacts WHERE %s`, cte, joinConditions(outerConditions))
+	if err := r.db.Get(&allEduCenters.Count, countQuery, args.values...); err != nil {
+		return models.AllEduCenters{}, err
+	}
+
+	sortColumn := eduCenterSortColumns[params.SortBy]
+	if params.Cursor != "" {
+		condition, err := keysetCondition(&args, params.Cursor, sortColumn[0], sortColumn[1], params.Order)
+		if err != nil {
+			return models.AllEduCenters{}, err
+		}
+		outerConditions = append(outerConditions, condition)
+	}
 
-	rows, err := r.db.Query(query)
+	// one more row is fetched to know whether next page exists
+	query := fmt.Sprintf(`%s
+	SELECT id, name, html_description, address, location, owner_id, cover_image, created_at, updated_at,
+	%s, instagram, telegram, phone_number, website, distance, (%s)::text AS sort_value
+	FROM edu_centers_with_rating_with_contacts
+	WHERE %s
+	ORDER BY %s %s, id %s
+	LIMIT %s OFFSET %s`,
+		cte, ratingStatsColumns, sortColumn[0], joinConditions(outerConditions), sortColumn[0], params.Order, params.Order,
+		args.add(params.Limit+1), args.add(pageOffset(params.PageQuery)))
+
+	rows, err := r.db.Query(query, args.values...)
 	if err != nil {
 		return models.AllEduCenters{}, err
 	}
 	defer rows.Close()
 
+	var sortValue, lastSortValue string
 	for rows.Next() {
 		var eduCenter models.EduCenter
 		err := rows.Scan(
@@ -73,16 +143,31 @@ func (r *EduCenterRepository) GetAllEduCenters() (models.AllEduCenters, error) {
 			&eduCenter.CreatedAt,
 			&eduCenter.UpdatedAt,
 			&eduCenter.Rating,
+			&eduCenter.RatingCount,
+			&eduCenter.WeightedRating,
+			&eduCenter.Distribution.Score1,
+			&eduCenter.Distribution.Score2,
+			&eduCenter.Distribution.Score3,
+			&eduCenter.Distribution.Score4,
+			&eduCenter.Distribution.Score5,
 			&eduCenter.Contacts.Instagram,
 			&eduCenter.Contacts.Telegram,
 			&eduCenter.Contacts.PhoneNumber,
 			&eduCenter.Contacts.Website,
-			&allEduCenters.Count,
+			&eduCenter.Distance,
+			&sortValue,
 		)
 		if err != nil {
 			return models.AllEduCenters{}, err
 		}
 
+		if len(allEduCenters.EduCenters) == params.Limit {
+			//there is next page
+			last := allEduCenters.EduCenters[params.Limit-1]
+			allEduCenters.NextCursor = encodeCursor(lastSortValue, last.ID)
+			break
+		}
+		lastSortValue = sortValue
 		allEduCenters.EduCenters = append(allEduCenters.EduCenters, eduCenter)
 	}
 
@@ -99,12 +184,13 @@ func (r *EduCenterRepository) CreateEduCenter(tx database.Transaction, eduCenter
 		VALUES (:name, :html_description, :address, POINT(:latitude, :longitude), :owner_id, :cover_image)
 		RETURNING id, name, html_description, address, location, owner_id, cover_image,created_at,updated_at
 	`
+	latitude, longitude := pointArgs(eduCenter.Location)
 	namedQueryArgs := map[string]interface{}{
 		"name":             eduCenter.Name,
 		"html_description": eduCenter.HtmlDescription,
 		"address":          eduCenter.Address,
-		"latitude":         eduCenter.Location.Latitude,
-		"longitude":        eduCenter.Location.Longitude,
+		"latitude":         latitude,
+		"longitude":        longitude,
 		"owner_id":         eduCenter.OwnerID,
 		"cover_image":      eduCenter.CoverImageUrl,
 	}
@@ -152,12 +238,10 @@ func (r *EduCenterRepository) CreateEduCenter(tx database.Transaction, eduCenter
 }
 
 func (r *EduCenterRepository) GetEduCenter(eduCenterID uuid.UUID) (models.EduCenter, error) {
-	query := `SELECT e.id, e.name, e.html_description, e.address, e.location, e.owner_id, e.cover_image,e.created_at,e.updated_at, COALESCE(ROUND(AVG(r.score),1),0) AS rating, c.instagram,c.telegram,c.website,c.phone_number 
+	query := fmt.Sprintf(`SELECT e.id, e.name, e.html_description, e.address, e.location, e.owner_id, e.cover_image,e.created_at,e.updated_at, %s, c.instagram,c.telegram,c.website,c.phone_number 
 	FROM edu_centers e 
-	LEFT JOIN ratings r ON e.id = r.edu_center_id 
 	LEFT JOIN contacts  c ON e.id = c.edu_center_id
-	WHERE e.id = $1 AND e.deleted_at IS NULL 
-	GROUP BY e.id,c.id`
+	WHERE e.id = $1 AND e.deleted_at IS NULL`, r.ratingPrior.ratingStatsSQL(models.EduCenterTarget, "e"))
 	rows, err := r.db.Query(query, eduCenterID)
 	if err != nil {
 		return models.EduCenter{}, err
@@ -165,34 +249,37 @@ func (r *EduCenterRepository) GetEduCenter(eduCenterID uuid.UUID) (models.EduCen
 	defer rows.Close()
 
 	var eduCenter models.EduCenter
-	if rows.Next() {
-		scanErr := rows.Scan(
-			&eduCenter.ID,
-			&eduCenter.Name,
-			&eduCenter.HtmlDescription,
-			&eduCenter.Address,
-			&eduCenter.Location,
-			&eduCenter.OwnerID,
-			&eduCenter.CoverImage,
-			&eduCenter.CreatedAt,
-			&eduCenter.UpdatedAt,
-			&eduCenter.Rating,
-			&eduCenter.Contacts.Instagram,
-			&eduCenter.Contacts.Telegram,
-			&eduCenter.Contacts.Website,
-			&eduCenter.Contacts.PhoneNumber,
-		)
-		if scanErr != nil {
-			return models.EduCenter{}, scanErr
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return models.EduCenter{}, err
 		}
+		return models.EduCenter{}, custom_errors.ErrEduCenterNotFound
 	}
-
-	err = rows.Err()
-	if err != nil {
-		if err == sql.ErrNoRows {
-			err = custom_errors.ErrEduCenterNotFound
-		}
-		return models.EduCenter{}, err
+	scanErr := rows.Scan(
+		&eduCenter.ID,
+		&eduCenter.Name,
+		&eduCenter.HtmlDescription,
+		&eduCenter.Address,
+		&eduCenter.Location,
+		&eduCenter.OwnerID,
+		&eduCenter.CoverImage,
+		&eduCenter.CreatedAt,
+		&eduCenter.UpdatedAt,
+		&eduCenter.Rating,
+		&eduCenter.RatingCount,
+		&eduCenter.WeightedRating,
+		&eduCenter.Distribution.Score1,
+		&eduCenter.Distribution.Score2,
+		&eduCenter.Distribution.Score3,
+		&eduCenter.Distribution.Score4,
+		&eduCenter.Distribution.Score5,
+		&eduCenter.Contacts.Instagram,
+		&eduCenter.Contacts.Telegram,
+		&eduCenter.Contacts.Website,
+		&eduCenter.Contacts.PhoneNumber,
+	)
+	if scanErr != nil {
+		return models.EduCenter{}, scanErr
 	}
 
 	return eduCenter, nil
@@ -209,17 +296,17 @@ func (r *EduCenterRepository) UpdateEduCenter(tx database.Transaction, eduCenter
     cover_image = :cover_image,
     updated_at = :updated_at
 	WHERE id = :id AND deleted_at IS NULL
-	RETURNING id, name, html_description, address, location, owner_id, cover_image, 
-	(SELECT COALESCE(ROUND(AVG(score), 1), 0) FROM ratings WHERE edu_center_id = :id) AS rating,
+	RETURNING id, name, html_description, address, location, owner_id, cover_image, rating_avg AS rating,
 	created_at, updated_at;
 `
+	latitude, longitude := pointArgs(eduCenter.Location)
 	queyArgs := map[string]interface{}{
 		"id":               eduCenter.ID,
 		"name":             eduCenter.Name,
 		"html_description": eduCenter.HtmlDescription,
 		"address":          eduCenter.Address,
-		"latitude":         eduCenter.Location.Latitude,
-		"longitude":        eduCenter.Location.Longitude,
+		"latitude":         latitude,
+		"longitude":        longitude,
 		"cover_image":      eduCenter.CoverImageUrl,
 		"updated_at":       eduCenter.UpdatedAt,
 	}
@@ -278,14 +365,59 @@ func (r *EduCenterRepository) DeleteEduCenter(eduCenterID uuid.UUID) error {
 	return nil
 }
 
-func (r *EduCenterRepository) GiveRating(rating models.EduCenterRating) error {
-	query := `INSERT INTO ratings (score,owner_id,edu_center_id) VALUES ($1,$2,$3)`
-	_, err := r.db.Exec(query, rating.Score, rating.OwnerID, rating.EduCenterID)
+// GiveRating creates rating of user or changes score of existing one and updates rating aggregates of center
+func (r *EduCenterRepository) GiveRating(tx database.Transaction, rating models.EduCenterRating) (models.EduCenterRating, error) {
+	if err := lockRatingTarget(tx, models.EduCenterTarget, rating.EduCenterID); err != nil {
+		return models.EduCenterRating{}, err
+	}
+
+	query := `INSERT INTO ratings (score,owner_id,edu_center_id) VALUES ($1,$2,$3)
+	ON CONFLICT (owner_id, edu_center_id) WHERE edu_center_id IS NOT NULL
+	DO UPDATE SET score = EXCLUDED.score, updated_at = CURRENT_TIMESTAMP
+	RETURNING id,score,owner_id,edu_center_id,created_at,updated_at`
+	var savedRating models.EduCenterRating
+	if err := tx.Get(&savedRating, query, rating.Score, rating.OwnerID, rating.EduCenterID); err != nil {
+		return models.EduCenterRating{}, err
+	}
+
+	if err := refreshRatingAggregates(tx, models.EduCenterTarget, rating.EduCenterID); err != nil {
+		return models.EduCenterRating{}, err
+	}
+
+	return savedRating, nil
+}
+
+func (r *EduCenterRepository) GetUserRating(eduCenterID uuid.UUID, userID uuid.UUID) (models.EduCenterRating, error) {
+	query := `SELECT id,score,owner_id,edu_center_id,created_at,updated_at FROM ratings WHERE edu_center_id = $1 AND owner_id = $2`
+	var rating models.EduCenterRating
+	if err := r.db.Get(&rating, query, eduCenterID, userID); err != nil {
+		if err == sql.ErrNoRows {
+			err = custom_errors.ErrRatingNotFound
+		}
+		return models.EduCenterRating{}, err
+	}
+
+	return rating, nil
+}
+
+func (r *EduCenterRepository) DeleteRating(tx database.Transaction, eduCenterID uuid.UUID, userID uuid.UUID) error {
+	if err := lockRatingTarget(tx, models.EduCenterTarget, eduCenterID); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`DELETE FROM ratings WHERE edu_center_id = $1 AND owner_id = $2`, eduCenterID, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if affected == 0 {
+		return custom_errors.ErrRatingNotFound
+	}
 
-	return nil
+	return refreshRatingAggregates(tx, models.EduCenterTarget, eduCenterID)
 }
 
 func (r *EduCenterRepository) BeginTransaction() (database.Transaction, error) {
@@ -358,44 +490,60 @@ func (r *EduCenterRepository) UpdateContacts(tx database.Transaction, contacts m
 	return updatedContacts, nil
 }
 
-func (r *EduCenterRepository) GetEduCenterByLocation(location models.NearEduCenterDto) ([]models.NearEduCenter, error) {
+// GetEduCenterByLocation finds visible centers around point. Centers are looked up through earthdistance
+// index on location, radius search is prefiltered by bounding box of the circle.
+func (r *EduCenterRepository) GetEduCenterByLocation(location models.NearEduCenterDto) (models.AllNearEduCenters, error) {
 	var (
-		query      string
-		eduCenters []models.NearEduCenter
+		args       queryArgs
+		origin     string
+		position   = "ll_to_earth(e.location[0], e.location[1])"
+		conditions = []string{"e.deleted_at IS NULL", "e.hidden_at IS NULL", "e.location IS NOT NULL"}
 	)
-	query = `SELECT
-    id,
-    name,
-    html_description,
-    address,
-    location,
-    owner_id,
-    6371 * ACOS(
-        SIN(RADIANS($1)) * SIN(RADIANS(location [0])) + COS(RADIANS($1)) * COS(RADIANS(location [0])) * COS(RADIANS($2 - location [1]))
-    ) AS distance,
-    created_at,
-    updated_at
-FROM
-    edu_centers
-WHERE
-    CASE
-        WHEN $5 <> 0 THEN 
-        6371 * ACOS(
-            SIN(RADIANS($1)) * SIN(RADIANS(location [0])) + COS(RADIANS($1)) * COS(RADIANS(location [0])) * COS(RADIANS($2 - location [1]))
-        ) <= $5 
-        ELSE TRUE 
-    END
-ORDER BY
-    distance
-LIMIT
-    $3 OFFSET $4	
-	`
+	//point is added to args once it is used, count query of unlimited distance doesn't use it
+	originSQL := func() string {
+		if origin == "" {
+			origin = fmt.Sprintf("ll_to_earth(%s::float8, %s::float8)", args.add(location.Latitude), args.add(location.Longitude))
+		}
+		return origin
+	}
+	if location.Distance > 0 {
+		radius := args.add(location.Distance*1000) + "::float8"
+		conditions = append(conditions,
+			fmt.Sprintf("earth_box(%s, %s) @> %s", originSQL(), radius, position),
+			fmt.Sprintf("earth_distance(%s, %s) <= %s", originSQL(), position, radius))
+	}
+
+	var allEduCenters models.AllNearEduCenters
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM edu_centers e WHERE %s`, joinConditions(conditions))
+	if err := r.db.Get(&allEduCenters.Count, countQuery, args.values...); err != nil {
+		return models.AllNearEduCenters{}, err
+	}
 
-	rows, err := r.db.Query(query, location.Latitude, location.Longitude, location.Limit, location.Offset, cast.ToString(location.Distance))
+	//best rated centers first when asked, nearest first otherwise
+	order := fmt.Sprintf("%s <-> %s", position, originSQL())
+	if location.SortBy == models.EduCenterSortRating {
+		order = "weighted_rating DESC, distance"
+	}
+	query := fmt.Sprintf(`SELECT e.id, e.name, e.html_description, e.address, e.location, e.owner_id,
+	earth_distance(%s, %s) / 1000 AS distance,
+	e.created_at, e.updated_at, %s,
+	COALESCE(c.instagram, '') AS instagram, COALESCE(c.telegram, '') AS telegram,
+	COALESCE(c.website, '') AS website, COALESCE(c.phone_number, '') AS phone_number
+	FROM edu_centers e
+	LEFT JOIN contacts c ON e.id = c.edu_center_id
+	WHERE %s
+	ORDER BY %s, e.id
+	LIMIT %s OFFSET %s`,
+		originSQL(), position, r.ratingPrior.ratingStatsSQL(models.EduCenterTarget, "e"),
+		joinConditions(conditions), order, args.add(location.Limit), args.add(location.Offset))
+
+	rows, err := r.db.Query(query, args.values...)
 	if err != nil {
-		return nil, err
+		return models.AllNearEduCenters{}, err
 	}
+	defer rows.Close()
 
+	allEduCenters.EduCenters = []models.NearEduCenter{}
 	for rows.Next() {
 		var (
 			updated_at sql.NullTime
@@ -411,17 +559,107 @@ LIMIT
 			&eduCenter.Distance,
 			&eduCenter.CreatedAt,
 			&updated_at,
+			&eduCenter.Rating,
+			&eduCenter.RatingCount,
+			&eduCenter.WeightedRating,
+			&eduCenter.Distribution.Score1,
+			&eduCenter.Distribution.Score2,
+			&eduCenter.Distribution.Score3,
+			&eduCenter.Distribution.Score4,
+			&eduCenter.Distribution.Score5,
+			&eduCenter.Contacts.Instagram,
+			&eduCenter.Contacts.Telegram,
+			&eduCenter.Contacts.Website,
+			&eduCenter.Contacts.PhoneNumber,
 		)
 		if err != nil {
-			return nil, err
+			return models.AllNearEduCenters{}, err
 		}
 		if updated_at.Valid {
 			eduCenter.UpdatedAt = updated_at.Time
 		}
-		eduCenters = append(eduCenters, eduCenter)
-		fmt.Println(eduCenter)
+		allEduCenters.EduCenters = append(allEduCenters.EduCenters, eduCenter)
 	}
 
-	return eduCenters, nil
+	if err := rows.Err(); err != nil {
+		return models.AllNearEduCenters{}, err
+	}
+
+	return allEduCenters, nil
+}
+
+func (r *EduCenterRepository) GetEduCenterOwner(eduCenterID uuid.UUID) (uuid.UUID, error) {
+	var ownerID uuid.UUID
+	err := r.db.Get(&ownerID, `SELECT owner_id FROM edu_centers WHERE id = $1 AND deleted_at IS NULL`, eduCenterID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = custom_errors.ErrEduCenterNotFound
+		}
+		return uuid.Nil, err
+	}
+	return ownerID, nil
+}
+
+// GetEduCenterMap groups centers inside bounding box into square cells of cellSize degrees.
+// Each cell is returned as cluster with centroid of its centers and its best rated center.
+func (r *EduCenterRepository) GetEduCenterMap(params models.EduCenterMapQuery, bbox models.BoundingBox, cellSize float64, cellsLimit int) ([]models.MapCluster, error) {
+	var args queryArgs
+	conditions := eduCenterFilterConditions(&args, params.EduCenterFilter)
+	conditions = append(conditions, fmt.Sprintf("e.location <@ box(point(%s::float8, %s::float8), point(%s::float8, %s::float8))",
+		args.add(bbox.MinLatitude), args.add(bbox.MinLongitude), args.add(bbox.MaxLatitude), args.add(bbox.MaxLongitude)))
+	cell := args.add(cellSize) + "::float8"
+
+	query := fmt.Sprintf(`WITH visible AS (
+		SELECT e.id, COALESCE(e.name, '') AS name, COALESCE(e.address, '') AS address, e.location, %s,
+		FLOOR(e.location[0] / %[2]s) AS cell_y, FLOOR(e.location[1] / %[2]s) AS cell_x
+		FROM edu_centers e
+		LEFT JOIN contacts c ON e.id = c.edu_center_id
+		WHERE %[3]s
+	), cells AS (
+		SELECT COUNT(*) AS count, AVG(location[0]) AS latitude, AVG(location[1]) AS longitude,
+		(ARRAY_AGG(id ORDER BY weighted_rating DESC, id))[1] AS top_id
+		FROM visible
+		GROUP BY cell_x, cell_y
+		ORDER BY count DESC
+		LIMIT %[4]s
+	)
+	SELECT cells.count, cells.latitude, cells.longitude,
+	v.id, v.name, v.address, v.location, v.rating, v.rating_count, v.weighted_rating
+	FROM cells
+	JOIN visible v ON v.id = cells.top_id
+	ORDER BY cells.count DESC, v.id`,
+		r.ratingPrior.ratingStatsSQL(models.EduCenterTarget, "e"), cell, joinConditions(conditions), args.add(cellsLimit))
+
+	rows, err := r.db.Query(query, args.values...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var clusters []models.MapCluster
+	for rows.Next() {
+		var cluster models.MapCluster
+		err := rows.Scan(
+			&cluster.Count,
+			&cluster.Centroid.Latitude,
+			&cluster.Centroid.Longitude,
+			&cluster.TopRated.ID,
+			&cluster.TopRated.Name,
+			&cluster.TopRated.Address,
+			&cluster.TopRated.Location,
+			&cluster.TopRated.Rating,
+			&cluster.TopRated.RatingCount,
+			&cluster.TopRated.WeightedRating,
+		)
+		if err != nil {
+			return nil, err
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
+	return clusters, nil
 }
@@ -0,0 +1,294 @@
+package repositories
+
+import (
+	"database/sql"
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type MatchRepositoryInterface interface {
+	GetStudentProfile(userID uuid.UUID) (models.StudentProfile, error)
+	SaveStudentProfile(profile models.StudentProfileDto) (models.StudentProfile, error)
+	Match(profile models.StudentProfile, params models.MatchQuery) (models.Matches, error)
+}
+
+type MatchRepository struct {
+	db          *sqlx.DB
+	ratingPrior ratingPrior
+}
+
+func NewMatchRepository(db *sqlx.DB) MatchRepositoryInterface {
+	return &MatchRepository{
+		db:          db,
+		ratingPrior: loadRatingPrior(),
+	}
+}
+
+const studentProfileColumns = `user_id, subjects, budget, COALESCE(currency, ''), preferred_days,
+	COALESCE(to_char(preferred_time_from, 'HH24:MI'), ''), COALESCE(to_char(preferred_time_to, 'HH24:MI'), ''),
+	max_distance, home_location, COALESCE(level, ''), created_at, updated_at`
+
+func (r *MatchRepository) GetStudentProfile(userID uuid.UUID) (models.StudentProfile, error) {
+	query := fmt.Sprintf(`SELECT %s FROM student_profiles WHERE user_id = $1`, studentProfileColumns)
+	profile, err := scanStudentProfile(r.db.QueryRow(query, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = custom_errors.ErrStudentProfileNotFound
+		}
+		return models.StudentProfile{}, err
+	}
+	return profile, nil
+}
+
+// SaveStudentProfile creates profile of user or replaces existing one
+func (r *MatchRepository) SaveStudentProfile(profile models.StudentProfileDto) (models.StudentProfile, error) {
+	var latitude, longitude *float64
+	if profile.HomeLocation != nil {
+		latitude, longitude = &profile.HomeLocation.Latitude, &profile.HomeLocation.Longitude
+	}
+	days := make(pq.Int64Array, 0, len(profile.PreferredDays))
+	for _, day := range profile.PreferredDays {
+		days = append(days, int64(day))
+	}
+
+	query := fmt.Sprintf(`INSERT INTO student_profiles (user_id, subjects, budget, currency, preferred_days,
+	preferred_time_from, preferred_time_to, max_distance, home_location, level)
+	VALUES ($1, COALESCE($2, '{}'::text[]), $3, NULLIF($4, ''), $5, NULLIF($6, '')::time, NULLIF($7, '')::time,
+	$8, POINT($9::float8, $10::float8), NULLIF($11, ''))
+	ON CONFLICT (user_id) DO UPDATE SET subjects = EXCLUDED.subjects, budget = EXCLUDED.budget,
+	currency = EXCLUDED.currency, preferred_days = EXCLUDED.preferred_days,
+	preferred_time_from = EXCLUDED.preferred_time_from, preferred_time_to = EXCLUDED.preferred_time_to,
+	max_distance = EXCLUDED.max_distance, home_location = EXCLUDED.home_location, level = EXCLUDED.level,
+	updated_at = CURRENT_TIMESTAMP
+	RETURNING %s`, studentProfileColumns)
+	row := r.db.QueryRow(query, profile.UserID, pq.Array(profile.Subjects), profile.Budget, profile.Currency, days,
+		profile.PreferredTimeFrom, profile.PreferredTimeTo, profile.MaxDistance, latitude, longitude, profile.Level)
+	return scanStudentProfile(row)
+}
+
+func scanStudentProfile(row *sql.Row) (models.StudentProfile, error) {
+	var (
+		profile      models.StudentProfile
+		days         pq.Int64Array
+		homeLocation *models.Point
+	)
+	err := row.Scan(
+		&profile.UserID,
+		pq.Array(&profile.Subjects),
+		&profile.Budget,
+		&profile.Currency,
+		&days,
+		&profile.PreferredTimeFrom,
+		&profile.PreferredTimeTo,
+		&profile.MaxDistance,
+		&homeLocation,
+		&profile.Level,
+		&profile.CreatedAt,
+		&profile.UpdatedAt,
+	)
+	if err != nil {
+		return models.StudentProfile{}, err
+	}
+	profile.HomeLocation = homeLocation
+	profile.PreferredDays = make([]int, 0, len(days))
+	for _, day := range days {
+		profile.PreferredDays = append(profile.PreferredDays, int(day))
+	}
+	return profile, nil
+}
+
+// weights of match factors in score, factors which can't be evaluated are left out of the weighted mean
+var matchFactorWeights = []struct {
+	factor string
+	weight float64
+}{
+	{"subjects", 3},
+	{"distance", 3},
+	{"rating", 2},
+	{"schedule", 2},
+	{"price", 2},
+	{"level", 1},
+}
+
+// distance in km at which distance score halves when profile has no max distance
+const matchHalfScoreDistance = 5
+
+// words of levels courses are labeled with in their names and descriptions
+const levelWordsTsQuery = `to_tsquery('simple', 'beginner | elementary | intermediate | advanced')`
+
+// Match scores visible courses and edu centers by the profile. Courses must mention one of subjects and be within
+// max distance when they are given. Subject score is the text rank of course for subjects, name matches weigh the most.
+// Edu centers are matched when one of their courses is, their factors are the best of their matching courses
+// except rating, which is their own.
+func (r *MatchRepository) Match(profile models.StudentProfile, params models.MatchQuery) (models.Matches, error) {
+	var (
+		args       queryArgs
+		conditions = []string{"c.deleted_at IS NULL", "c.hidden_at IS NULL", "e.deleted_at IS NULL", "e.hidden_at IS NULL"}
+		distance   = "NULL::float8"
+		subjects   = "NULL::float8"
+		level      = "NULL::float8"
+		schedule   = "NULL::float8"
+		price      = "NULL::float8"
+		priceJoin  string
+		scores     = map[string]string{
+			"subjects": "LEAST(1, 2 * subjects_rank)",
+			"distance": fmt.Sprintf("1 / (1 + distance / %d)", matchHalfScoreDistance),
+			"rating":   "weighted_rating / 5",
+			"schedule": "schedule_fit",
+			"price":    "price_fit",
+			"level":    "level_match",
+		}
+	)
+
+	if len(profile.Subjects) > 0 {
+		var queries []string
+		for _, subject := range profile.Subjects {
+			queries = append(queries, fmt.Sprintf("plainto_tsquery('simple', %s::text)", args.add(subject)))
+		}
+		tsQuery := "(" + strings.Join(queries, " || ") + ")"
+		subjects = fmt.Sprintf("ts_rank_cd(c.search_vector, %s, 32)", tsQuery)
+		conditions = append(conditions, "c.search_vector @@ "+tsQuery)
+	}
+	if profile.HomeLocation != nil {
+		distance = distanceSQL("e.location", args.add(profile.HomeLocation.Latitude)+"::float8", args.add(profile.HomeLocation.Longitude)+"::float8")
+		if profile.MaxDistance != nil {
+			maxDistance := args.add(*profile.MaxDistance) + "::float8"
+			conditions = append(conditions, fmt.Sprintf("e.location IS NOT NULL AND %s <= %s", distance, maxDistance))
+			scores["distance"] = fmt.Sprintf("GREATEST(0, 1 - distance / %s)", maxDistance)
+		}
+	}
+	//courses not labeled with any level can't be evaluated by it
+	if profile.Level != "" {
+		level = fmt.Sprintf(`CASE WHEN c.search_vector @@ phraseto_tsquery('simple', %s::text) THEN 1
+		WHEN c.search_vector @@ %s THEN 0 END`, args.add(strings.ReplaceAll(profile.Level, "_", " ")), levelWordsTsQuery)
+	}
+
+	//schedule fit is the share of slots of the best fitting running or upcoming group within preferred days and times
+	var slotFits []string
+	if len(profile.PreferredDays) > 0 {
+		days := make(pq.Int64Array, 0, len(profile.PreferredDays))
+		for _, day := range profile.PreferredDays {
+			days = append(days, int64(day))
+		}
+		slotFits = append(slotFits, fmt.Sprintf("s.day_of_week = ANY(%s::int[])", args.add(days)))
+	}
+	if profile.PreferredTimeFrom != "" {
+		slotFits = append(slotFits, fmt.Sprintf("s.start_time >= %s::time", args.add(profile.PreferredTimeFrom)))
+	}
+	if profile.PreferredTimeTo != "" {
+		slotFits = append(slotFits, fmt.Sprintf("s.end_time <= %s::time", args.add(profile.PreferredTimeTo)))
+	}
+	if len(slotFits) > 0 {
+		schedule = fmt.Sprintf(`(SELECT MAX(f.fit) FROM (
+			SELECT AVG(CASE WHEN %s THEN 1 ELSE 0 END) AS fit
+			FROM course_schedules g JOIN schedule_slots s ON s.schedule_id = g.id
+			WHERE g.course_id = c.id AND (g.end_date IS NULL OR g.end_date >= CURRENT_DATE)
+			GROUP BY g.id
+		) f)`, joinConditions(slotFits))
+	}
+
+	//budget is monthly, so only monthly prices in the same currency are evaluated. Prices within budget fit fully,
+	//fit falls to zero at double budget
+	if profile.Budget != nil && profile.Currency != "" {
+		budget := args.add(*profile.Budget) + "::numeric"
+		price = fmt.Sprintf(`CASE WHEN c.currency = %s AND c.price_unit = '%s' THEN LEAST(1, GREATEST(0, 2 - %s / %s)) END`,
+			args.add(profile.Currency), models.PricePerMonth, effectivePriceSQL, budget)
+		priceJoin = coursePriceJoin
+	}
+
+	var weightedScores, weights, scoreColumns []string
+	for _, factor := range matchFactorWeights {
+		column := fmt.Sprintf(`"scores.%s"`, factor.factor)
+		weight := strconv.FormatFloat(factor.weight, 'f', -1, 64)
+		scoreColumns = append(scoreColumns, fmt.Sprintf("(%s)::float8 AS %s", scores[factor.factor], column))
+		weightedScores = append(weightedScores, fmt.Sprintf("COALESCE(%s * %s, 0)", column, weight))
+		weights = append(weights, fmt.Sprintf("CASE WHEN %s IS NULL THEN 0 ELSE %s END", column, weight))
+	}
+
+	typeCondition := "TRUE"
+	if params.Type != "" {
+		typeCondition = "type = " + args.add(params.Type)
+	}
+
+	cte := fmt.Sprintf(`WITH course_candidates AS (
+			SELECT c.id, c.name, COALESCE(c.teacher, '') AS teacher, c.edu_center_id, e.name AS edu_center_name, e.location,
+			%s AS distance, %s AS subjects_rank, %s AS level_match, %s AS schedule_fit, %s AS price_fit, %s
+			FROM courses c
+			JOIN edu_centers e ON e.id = c.edu_center_id
+			%s
+			WHERE %s
+		), candidates AS (
+			SELECT '%s' AS type, * FROM course_candidates
+			UNION ALL
+			SELECT '%s', e.id, e.name, '', e.id, e.name, e.location,
+			m.distance, m.subjects_rank, m.level_match, m.schedule_fit, m.price_fit, %s
+			FROM (
+				SELECT edu_center_id, MAX(distance) AS distance, MAX(subjects_rank) AS subjects_rank, MAX(level_match) AS level_match,
+				MAX(schedule_fit) AS schedule_fit, MAX(price_fit) AS price_fit
+				FROM course_candidates
+				GROUP BY edu_center_id
+			) m
+			JOIN edu_centers e ON e.id = m.edu_center_id
+		), scored AS (
+			SELECT type, id, name, teacher, edu_center_id, edu_center_name, location, distance, rating, %s
+			FROM candidates
+			WHERE %s
+		)`, distance, subjects, level, schedule, price, r.ratingPrior.ratingStatsSQL(models.CourseTarget, "c"),
+		priceJoin, joinConditions(conditions), models.MatchTypeCourse, models.MatchTypeEduCenter,
+		r.ratingPrior.ratingStatsSQL(models.EduCenterTarget, "e"), strings.Join(scoreColumns, ", "), typeCondition)
+
+	matches := models.Matches{Matches: []models.Match{}}
+	countQuery := fmt.Sprintf(`%s SELECT COUNT(*) FROM scored`, cte)
+	if err := r.db.Get(&matches.Count, countQuery, args.values...); err != nil {
+		return models.Matches{}, err
+	}
+	if matches.Count == 0 {
+		return matches, nil
+	}
+
+	pageCondition := "TRUE"
+	if params.Cursor != "" {
+		condition, err := keysetCondition(&args, params.Cursor, "score", "numeric", params.Order)
+		if err != nil {
+			return models.Matches{}, err
+		}
+		pageCondition = condition
+	}
+
+	//one more row is fetched to know whether next page exists
+	query := fmt.Sprintf(`%[1]s
+		SELECT m.*, m.score::text AS sort_value FROM (
+			SELECT *, COALESCE(ROUND(((%[2]s) / NULLIF(%[3]s, 0))::numeric, 4), 0) AS score
+			FROM scored
+		) m
+		WHERE %[4]s
+		ORDER BY score %[5]s, id %[5]s
+		LIMIT %[6]s OFFSET %[7]s`,
+		cte, strings.Join(weightedScores, " + "), strings.Join(weights, " + "), pageCondition, params.Order,
+		args.add(params.Limit+1), args.add(pageOffset(params.PageQuery)))
+
+	var rows []struct {
+		models.Match
+		SortValue string `db:"sort_value"`
+	}
+	if err := r.db.Select(&rows, query, args.values...); err != nil {
+		return models.Matches{}, err
+	}
+	if len(rows) > params.Limit {
+		//there is next page
+		last := rows[params.Limit-1]
+		matches.NextCursor = encodeCursor(last.SortValue, last.ID)
+		rows = rows[:params.Limit]
+	}
+	for _, row := range rows {
+		matches.Matches = append(matches.Matches, row.Match)
+	}
+	return matches, nil
+}
@@ -0,0 +1,244 @@
+package repositories
+
+import (
+	"database/sql"
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
+	database "edumatch/pkg/db"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ModerationRepositoryInterface interface {
+	CreateReport(report models.CreateReportDto) (models.Report, error)
+	GetReports(params models.ReportQuery) (models.AllReports, error)
+	GetReport(reportID uuid.UUID) (models.Report, error)
+	BeginTransaction() (database.Transaction, error)
+	ApplyModeration(tx database.Transaction, target models.ReportTarget, targetID uuid.UUID, action string) error
+	ResolveReports(tx database.Transaction, report models.Report, decision models.ModerationDecisionDto) (models.Report, error)
+}
+
+type ModerationRepository struct {
+	db *sqlx.DB
+}
+
+func NewModerationRepository(db *sqlx.DB) ModerationRepositoryInterface {
+	return &ModerationRepository{
+		db: db,
+	}
+}
+
+// reportTargetTables maps reportable items to their tables
+var reportTargetTables = map[models.ReportTarget]string{
+	models.ReportEduCenter: "edu_centers",
+	models.ReportCourse:    "courses",
+	models.ReportRating:    "ratings",
+}
+
+const reportColumns = `id, reporter_id, target_type, target_id, reason, status, decision, decision_note, decided_by, decided_at, created_at`
+
+func (r *ModerationRepository) CreateReport(report models.CreateReportDto) (models.Report, error) {
+	//only existing items can be reported
+	query := fmt.Sprintf(`INSERT INTO reports (reporter_id, target_type, target_id, reason)
+	SELECT $1::uuid, $2, t.id, $4 FROM %s t WHERE t.id = $3
+	RETURNING %s`, reportTargetTables[report.TargetType], reportColumns)
+
+	var createdReport models.Report
+	err := r.db.Get(&createdReport, query, report.ReporterID, string(report.TargetType), report.TargetID, report.Reason)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = custom_errors.ErrReportTargetNotFound
+		}
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			err = custom_errors.ErrReportExists
+		}
+		return models.Report{}, err
+	}
+
+	return createdReport, nil
+}
+
+func (r *ModerationRepository) GetReports(params models.ReportQuery) (models.AllReports, error) {
+	var (
+		args       queryArgs
+		conditions []string
+	)
+	if params.Status != "" {
+		conditions = append(conditions, fmt.Sprintf("status = %s", args.add(params.Status)))
+	}
+	if params.TargetType != "" {
+		conditions = append(conditions, fmt.Sprintf("target_type = %s", args.add(params.TargetType)))
+	}
+
+	var allReports models.AllReports
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM reports WHERE %s`, joinConditions(conditions))
+	if err := r.db.Get(&allReports.Count, countQuery, args.values...); err != nil {
+		return models.AllReports{}, err
+	}
+
+	if params.Cursor != "" {
+		condition, err := keysetCondition(&args, params.Cursor, "created_at", "timestamptz", params.Order)
+		if err != nil {
+			return models.AllReports{}, err
+		}
+		conditions = append(conditions, condition)
+	}
+
+	// one more row is fetched to know whether next page exists
+	query := fmt.Sprintf(`SELECT %s, created_at::text AS sort_value FROM reports
+	WHERE %s
+	ORDER BY created_at %s, id %s
+	LIMIT %s OFFSET %s`,
+		reportColumns, joinConditions(conditions), params.Order, params.Order,
+		args.add(params.Limit+1), args.add(pageOffset(params.PageQuery)))
+
+	rows, err := r.db.Queryx(query, args.values...)
+	if err != nil {
+		return models.AllReports{}, err
+	}
+	defer rows.Close()
+
+	var lastSortValue string
+	for rows.Next() {
+		var row struct {
+			models.Report
+			SortValue string `db:"sort_value"`
+		}
+		if err := rows.StructScan(&row); err != nil {
+			return models.AllReports{}, err
+		}
+
+		if len(allReports.Reports) == params.Limit {
+			//there is next page
+			last := allReports.Reports[params.Limit-1]
+			allReports.NextCursor = encodeCursor(lastSortValue, last.ID)
+			break
+		}
+		lastSortValue = row.SortValue
+		allReports.Reports = append(allReports.Reports, row.Report)
+	}
+
+	if err := rows.Err(); err != nil {
+		return models.AllReports{}, err
+	}
+
+	return allReports, nil
+}
+
+func (r *ModerationRepository) GetReport(reportID uuid.UUID) (models.Report, error) {
+	var report models.Report
+	if err := r.db.Get(&report, `SELECT `+reportColumns+` FROM reports WHERE id = $1`, reportID); err != nil {
+		if err == sql.ErrNoRows {
+			err = custom_errors.ErrReportNotFound
+		}
+		return models.Report{}, err
+	}
+	return report, nil
+}
+
+func (r *ModerationRepository) BeginTransaction() (database.Transaction, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	return &database.CustomTx{Tx: tx}, nil
+}
+
+// ApplyModeration changes visibility of reported item according to action
+func (r *ModerationRepository) ApplyModeration(tx database.Transaction, target models.ReportTarget, targetID uuid.UUID, action string) error {
+	table := reportTargetTables[target]
+	var (
+		query string
+		args  = []interface{}{targetID}
+	)
+	switch action {
+	case models.ModerationHide:
+		query = fmt.Sprintf(`UPDATE %s SET hidden_at = CURRENT_TIMESTAMP WHERE id = $1`, table)
+	case models.ModerationRestore:
+		query = fmt.Sprintf(`UPDATE %s SET hidden_at = NULL WHERE id = $1`, table)
+		if target != models.ReportRating {
+			//items deleted by their owners stay deleted
+			query = fmt.Sprintf(`UPDATE %s SET hidden_at = NULL,
+			deleted_at = CASE WHEN moderation_deleted_at IS NULL THEN deleted_at END, moderation_deleted_at = NULL
+			WHERE id = $1`, table)
+		}
+	case models.ModerationDelete:
+		//ratings have no soft delete
+		query = `DELETE FROM ratings WHERE id = $1`
+		if target != models.ReportRating {
+			query = fmt.Sprintf(`UPDATE %s SET deleted_at = $2, moderation_deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL`, table)
+			args = append(args, time.Now().UTC())
+		}
+	default:
+		return nil
+	}
+
+	//aggregates of rated item follow visibility of its ratings
+	var ratedItem struct {
+		Target models.RatingTarget `db:"target"`
+		ID     uuid.UUID           `db:"id"`
+	}
+	if target == models.ReportRating {
+		ratedQuery := `SELECT CASE WHEN course_id IS NULL THEN 'edu_center' ELSE 'course' END AS target,
+		COALESCE(course_id, edu_center_id) AS id FROM ratings WHERE id = $1`
+		if err := tx.Get(&ratedItem, ratedQuery, targetID); err != nil {
+			if err == sql.ErrNoRows {
+				err = custom_errors.ErrReportTargetNotFound
+			}
+			return err
+		}
+		if err := lockRatingTarget(tx, ratedItem.Target, ratedItem.ID); err != nil {
+			return err
+		}
+	}
+
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return custom_errors.ErrReportTargetNotFound
+	}
+
+	if target == models.ReportRating {
+		return refreshRatingAggregates(tx, ratedItem.Target, ratedItem.ID)
+	}
+
+	return nil
+}
+
+// ResolveReports records decision on pending report and on all pending reports of the same item.
+// Decision of resolved report is kept, so concurrent decisions can't both succeed.
+func (r *ModerationRepository) ResolveReports(tx database.Transaction, report models.Report, decision models.ModerationDecisionDto) (models.Report, error) {
+	var resolved bool
+	query := `WITH resolved AS (
+		UPDATE reports
+		SET status = $1, decision = $2, decision_note = $3, decided_by = $4, decided_at = CURRENT_TIMESTAMP
+		WHERE status = $8 AND (id = $5 OR (target_type = $6 AND target_id = $7))
+		RETURNING id
+	)
+	SELECT EXISTS (SELECT 1 FROM resolved WHERE id = $5)`
+	err := tx.Get(&resolved, query, models.ReportResolved, decision.Action, decision.Note, decision.ModeratorID,
+		report.ID, string(report.TargetType), report.TargetID, models.ReportPending)
+	if err != nil {
+		return models.Report{}, err
+	}
+	if !resolved {
+		return models.Report{}, custom_errors.ErrReportResolved
+	}
+
+	var resolvedReport models.Report
+	if err := tx.Get(&resolvedReport, `SELECT `+reportColumns+` FROM reports WHERE id = $1`, report.ID); err != nil {
+		return models.Report{}, err
+	}
+
+	return resolvedReport, nil
+}
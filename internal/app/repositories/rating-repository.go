@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"database/sql"
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
+	database "edumatch/pkg/db"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type RatingRepositoryInterface interface {
+	RepairRatingAggregates() (models.RepairedAggregates, error)
+}
+
+type RatingRepository struct {
+	db *sqlx.DB
+}
+
+func NewRatingRepository(db *sqlx.DB) RatingRepositoryInterface {
+	return &RatingRepository{
+		db: db,
+	}
+}
+
+// ratingTargetTables maps rating targets to tables storing their rating aggregates
+var ratingTargetTables = map[models.RatingTarget]string{
+	models.EduCenterTarget: "edu_centers",
+	models.CourseTarget:    "courses",
+}
+
+var ratingTargetNotFound = map[models.RatingTarget]error{
+	models.EduCenterTarget: custom_errors.ErrEduCenterNotFound,
+	models.CourseTarget:    custom_errors.ErrCourseNotFound,
+}
+
+// RepairRatingAggregates recomputes stored aggregates of all centers and courses which drifted from ratings table
+func (r *RatingRepository) RepairRatingAggregates() (models.RepairedAggregates, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return models.RepairedAggregates{}, err
+	}
+	customTx := &database.CustomTx{Tx: tx}
+
+	//ratings can't change while aggregates are recomputed
+	if _, err := customTx.Exec(`LOCK TABLE ratings IN SHARE MODE`); err != nil {
+		customTx.Rollback()
+		return models.RepairedAggregates{}, err
+	}
+
+	var repaired models.RepairedAggregates
+	if repaired.EduCenters, err = updateRatingAggregates(customTx, models.EduCenterTarget, "TRUE"); err != nil {
+		customTx.Rollback()
+		return models.RepairedAggregates{}, err
+	}
+	if repaired.Courses, err = updateRatingAggregates(customTx, models.CourseTarget, "TRUE"); err != nil {
+		customTx.Rollback()
+		return models.RepairedAggregates{}, err
+	}
+
+	if err := customTx.Commit(); err != nil {
+		return models.RepairedAggregates{}, err
+	}
+
+	return repaired, nil
+}
+
+// lockRatingTarget locks rated item till the end of transaction, so changes of its ratings are applied one by one
+func lockRatingTarget(tx database.Transaction, target models.RatingTarget, targetID uuid.UUID) error {
+	var id uuid.UUID
+	query := fmt.Sprintf(`SELECT id FROM %s WHERE id = $1 FOR UPDATE`, ratingTargetTables[target])
+	if err := tx.Get(&id, query, targetID); err != nil {
+		if err == sql.ErrNoRows {
+			err = ratingTargetNotFound[target]
+		}
+		return err
+	}
+	return nil
+}
+
+// refreshRatingAggregates recomputes stored aggregates of item after its ratings changed
+func refreshRatingAggregates(tx database.Transaction, target models.RatingTarget, targetID uuid.UUID) error {
+	_, err := updateRatingAggregates(tx, target, "x.id = $1", targetID)
+	return err
+}
+
+// updateRatingAggregates recomputes stored aggregates of items matching condition from their visible ratings
+// and returns number of items whose aggregates changed
+func updateRatingAggregates(tx database.Transaction, target models.RatingTarget, condition string, args ...interface{}) (int64, error) {
+	query := fmt.Sprintf(`UPDATE %[1]s t
+	SET rating_sum = a.sum, rating_count = a.count, rating_avg = a.avg,
+	score_1 = a.score_1, score_2 = a.score_2, score_3 = a.score_3, score_4 = a.score_4, score_5 = a.score_5
+	FROM (
+		SELECT x.id, COALESCE(SUM(r.score), 0) AS sum, COUNT(r.id) AS count, COALESCE(ROUND(AVG(r.score), 1), 0) AS avg,
+		COUNT(r.id) FILTER (WHERE r.score = 1) AS score_1,
+		COUNT(r.id) FILTER (WHERE r.score = 2) AS score_2,
+		COUNT(r.id) FILTER (WHERE r.score = 3) AS score_3,
+		COUNT(r.id) FILTER (WHERE r.score = 4) AS score_4,
+		COUNT(r.id) FILTER (WHERE r.score = 5) AS score_5
+		FROM %[1]s x
+		LEFT JOIN ratings r ON r.%[2]s = x.id AND r.hidden_at IS NULL
+		WHERE %[3]s
+		GROUP BY x.id
+	) a
+	WHERE t.id = a.id AND (t.rating_sum, t.rating_count, t.rating_avg, t.score_1, t.score_2, t.score_3, t.score_4, t.score_5)
+	IS DISTINCT FROM (a.sum, a.count, a.avg, a.score_1, a.score_2, a.score_3, a.score_4, a.score_5)`,
+		ratingTargetTables[target], ratingTargetColumns[target], condition)
+
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,207 @@
+package repositories
+
+import (
+	"database/sql"
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type RatingResponseRepositoryInterface interface {
+	GetRatingCenterOwner(ratingID uuid.UUID) (uuid.UUID, error)
+	SaveResponse(ratingID uuid.UUID, response models.RatingResponseDto) (models.RatingResponse, error)
+	DeleteResponse(ratingID uuid.UUID) error
+	GetEduCenterRatings(eduCenterID uuid.UUID, params models.PageQuery) (models.AllRatingsWithResponses, error)
+	GetUnansweredRatings(ownerID uuid.UUID, params models.UnansweredRatingsQuery) (models.AllRatingsWithResponses, error)
+}
+
+type RatingResponseRepository struct {
+	db *sqlx.DB
+}
+
+func NewRatingResponseRepository(db *sqlx.DB) RatingResponseRepositoryInterface {
+	return &RatingResponseRepository{
+		db: db,
+	}
+}
+
+// GetRatingCenterOwner returns owner of center the rating (or its rated course) belongs to, ratings of deleted
+// centers and courses aren't found
+func (r *RatingResponseRepository) GetRatingCenterOwner(ratingID uuid.UUID) (uuid.UUID, error) {
+	var ownerID uuid.UUID
+	query := `SELECT e.owner_id FROM ratings r
+	LEFT JOIN courses c ON c.id = r.course_id
+	JOIN edu_centers e ON e.id = COALESCE(r.edu_center_id, c.edu_center_id)
+	WHERE r.id = $1 AND r.hidden_at IS NULL AND e.deleted_at IS NULL AND (c.id IS NULL OR c.deleted_at IS NULL)`
+	if err := r.db.Get(&ownerID, query, ratingID); err != nil {
+		if err == sql.ErrNoRows {
+			err = custom_errors.ErrRatingNotFound
+		}
+		return uuid.Nil, err
+	}
+	return ownerID, nil
+}
+
+// SaveResponse creates response to rating or edits existing one
+func (r *RatingResponseRepository) SaveResponse(ratingID uuid.UUID, response models.RatingResponseDto) (models.RatingResponse, error) {
+	query := `INSERT INTO rating_responses (rating_id, owner_id, body) VALUES ($1, $2, $3)
+	ON CONFLICT (rating_id) DO UPDATE SET owner_id = EXCLUDED.owner_id, body = EXCLUDED.body, updated_at = CURRENT_TIMESTAMP
+	RETURNING id, rating_id, owner_id, body, created_at, updated_at`
+	var savedResponse models.RatingResponse
+	if err := r.db.Get(&savedResponse, query, ratingID, response.OwnerID, response.Body); err != nil {
+		return models.RatingResponse{}, err
+	}
+	return savedResponse, nil
+}
+
+func (r *RatingResponseRepository) DeleteResponse(ratingID uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM rating_responses WHERE rating_id = $1`, ratingID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return custom_errors.ErrResponseNotFound
+	}
+	return nil
+}
+
+func (r *RatingResponseRepository) GetEduCenterRatings(eduCenterID uuid.UUID, params models.PageQuery) (models.AllRatingsWithResponses, error) {
+	var exists bool
+	if err := r.db.Get(&exists, `SELECT EXISTS(SELECT 1 FROM edu_centers WHERE id = $1 AND deleted_at IS NULL AND hidden_at IS NULL)`, eduCenterID); err != nil {
+		return models.AllRatingsWithResponses{}, err
+	}
+	if !exists {
+		return models.AllRatingsWithResponses{}, custom_errors.ErrEduCenterNotFound
+	}
+
+	var args queryArgs
+	condition := fmt.Sprintf("e.id = %s", args.add(eduCenterID))
+	return r.getRatings(&args, []string{condition}, params)
+}
+
+func (r *RatingResponseRepository) GetUnansweredRatings(ownerID uuid.UUID, params models.UnansweredRatingsQuery) (models.AllRatingsWithResponses, error) {
+	var args queryArgs
+	conditions := []string{
+		fmt.Sprintf("e.owner_id = %s", args.add(ownerID)),
+		fmt.Sprintf("r.score <= %s", args.add(params.MaxScore)),
+		"rr.id IS NULL",
+	}
+	return r.getRatings(&args, conditions, params.PageQuery)
+}
+
+// getRatings lists visible ratings of centers and their courses matching conditions, newest first by default
+func (r *RatingResponseRepository) getRatings(args *queryArgs, conditions []string, params models.PageQuery) (models.AllRatingsWithResponses, error) {
+	conditions = append(conditions, "r.hidden_at IS NULL", "e.deleted_at IS NULL", "(c.id IS NULL OR c.deleted_at IS NULL)")
+	cte := fmt.Sprintf(`WITH center_ratings AS (
+		SELECT r.id, r.score,
+		CASE WHEN r.course_id IS NULL THEN 'edu_center' ELSE 'course' END AS target_type,
+		COALESCE(r.course_id, r.edu_center_id) AS target_id, e.id AS edu_center_id,
+		u.id AS rater_id, COALESCE(u.first_name, '') AS first_name, COALESCE(u.last_name, '') AS last_name,
+		COALESCE(u.username, '') AS username, COALESCE(u.avatar, '') AS avatar,
+		rr.id AS response_id, rr.owner_id AS response_owner_id, rr.body AS response_body,
+		rr.created_at AS response_created_at, rr.updated_at AS response_updated_at,
+		r.created_at, r.updated_at
+		FROM ratings r
+		LEFT JOIN courses c ON c.id = r.course_id
+		JOIN edu_centers e ON e.id = COALESCE(r.edu_center_id, c.edu_center_id)
+		JOIN users u ON u.id = r.owner_id
+		LEFT JOIN rating_responses rr ON rr.rating_id = r.id
+		WHERE %s
+	)`, joinConditions(conditions))
+
+	var allRatings models.AllRatingsWithResponses
+	if err := r.db.Get(&allRatings.Count, cte+` SELECT COUNT(*) FROM center_ratings`, args.values...); err != nil {
+		return models.AllRatingsWithResponses{}, err
+	}
+
+	var pageConditions []string
+	if params.Cursor != "" {
+		condition, err := keysetCondition(args, params.Cursor, "created_at", "timestamptz", params.Order)
+		if err != nil {
+			return models.AllRatingsWithResponses{}, err
+		}
+		pageConditions = append(pageConditions, condition)
+	}
+
+	// one more row is fetched to know whether next page exists
+	query := fmt.Sprintf(`%s
+	SELECT *, created_at::text AS sort_value FROM center_ratings
+	WHERE %s
+	ORDER BY created_at %s, id %s
+	LIMIT %s OFFSET %s`,
+		cte, joinConditions(pageConditions), params.Order, params.Order,
+		args.add(params.Limit+1), args.add(pageOffset(params)))
+
+	rows, err := r.db.Query(query, args.values...)
+	if err != nil {
+		return models.AllRatingsWithResponses{}, err
+	}
+	defer rows.Close()
+
+	var sortValue, lastSortValue string
+	for rows.Next() {
+		var (
+			rating            models.RatingWithResponse
+			responseID        *uuid.UUID
+			responseOwnerID   *uuid.UUID
+			responseBody      sql.NullString
+			responseCreatedAt sql.NullTime
+			responseUpdatedAt sql.NullTime
+		)
+		err := rows.Scan(
+			&rating.ID,
+			&rating.Score,
+			&rating.TargetType,
+			&rating.TargetID,
+			&rating.EduCenterID,
+			&rating.Rater.ID,
+			&rating.Rater.FirstName,
+			&rating.Rater.LastName,
+			&rating.Rater.Username,
+			&rating.Rater.Avatar,
+			&responseID,
+			&responseOwnerID,
+			&responseBody,
+			&responseCreatedAt,
+			&responseUpdatedAt,
+			&rating.CreatedAt,
+			&rating.UpdatedAt,
+			&sortValue,
+		)
+		if err != nil {
+			return models.AllRatingsWithResponses{}, err
+		}
+		if responseID != nil {
+			rating.Response = &models.RatingResponse{
+				ID:        *responseID,
+				RatingID:  rating.ID,
+				OwnerID:   *responseOwnerID,
+				Body:      responseBody.String,
+				CreatedAt: nullTime(responseCreatedAt),
+				UpdatedAt: nullTime(responseUpdatedAt),
+			}
+		}
+
+		if len(allRatings.Ratings) == params.Limit {
+			//there is next page
+			last := allRatings.Ratings[params.Limit-1]
+			allRatings.NextCursor = encodeCursor(lastSortValue, last.ID)
+			break
+		}
+		lastSortValue = sortValue
+		allRatings.Ratings = append(allRatings.Ratings, rating)
+	}
+
+	if err := rows.Err(); err != nil {
+		return models.AllRatingsWithResponses{}, err
+	}
+
+	return allRatings, nil
+}
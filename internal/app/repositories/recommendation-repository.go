@@ -0,0 +1,208 @@
+package repositories
+
+import (
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
+	database "edumatch/pkg/db"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type RecommendationRepositoryInterface interface {
+	ComputeSimilarities() (models.ComputedSimilarities, error)
+	GetSimilarCourses(courseID uuid.UUID, limit int) ([]models.Recommendation, error)
+	GetUserRecommendations(userID uuid.UUID, limit int) ([]models.Recommendation, error)
+	GetPopularRecommendations(location *models.Point, radius float64, limit int) ([]models.Recommendation, error)
+}
+
+type RecommendationRepository struct {
+	db          *sqlx.DB
+	ratingPrior ratingPrior
+}
+
+func NewRecommendationRepository(db *sqlx.DB) RecommendationRepositoryInterface {
+	return &RecommendationRepository{
+		db:          db,
+		ratingPrior: loadRatingPrior(),
+	}
+}
+
+const (
+	// items rated by fewer users in common are not compared
+	minCommonRaters = 2
+	// similarity of items with few common raters is shrunk towards zero by n / (n + shrinkage)
+	similarityShrinkage = 10
+	// the most similar items kept for each item
+	maxSimilarItems = 50
+)
+
+// ComputeSimilarities replaces item to item similarities by adjusted cosine of ratings: scores are centered
+// by mean score of each user, so users rating everything high don't make all their items similar.
+func (r *RecommendationRepository) ComputeSimilarities() (models.ComputedSimilarities, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return models.ComputedSimilarities{}, err
+	}
+	customTx := &database.CustomTx{Tx: tx}
+
+	//another instance of application is already computing them
+	var locked bool
+	if err := customTx.Get(&locked, `SELECT pg_try_advisory_xact_lock(hashtext('item_similarities'))`); err != nil || !locked {
+		customTx.Rollback()
+		if err == nil {
+			err = custom_errors.ErrSimilaritiesRunning
+		}
+		return models.ComputedSimilarities{}, err
+	}
+
+	if _, err := customTx.Exec(`DELETE FROM item_similarities`); err != nil {
+		customTx.Rollback()
+		return models.ComputedSimilarities{}, err
+	}
+
+	query := `INSERT INTO item_similarities (item_type, item_id, similar_type, similar_id, score, common_raters)
+	WITH centered AS (
+		SELECT r.owner_id,
+		CASE WHEN r.edu_center_id IS NOT NULL THEN 'edu_center' ELSE 'course' END AS item_type,
+		COALESCE(r.edu_center_id, r.course_id) AS item_id,
+		r.score - AVG(r.score) OVER (PARTITION BY r.owner_id) AS score
+		FROM ratings r
+		WHERE r.hidden_at IS NULL
+	), norms AS (
+		SELECT item_id, SQRT(SUM(score * score)) AS norm
+		FROM centered
+		GROUP BY item_id
+	), similarities AS (
+		SELECT a.item_type, a.item_id, b.item_type AS similar_type, b.item_id AS similar_id,
+		SUM(a.score * b.score) / (na.norm * nb.norm) * COUNT(*) / (COUNT(*) + $2) AS score,
+		COUNT(*) AS common_raters
+		FROM centered a
+		JOIN centered b ON b.owner_id = a.owner_id AND b.item_id <> a.item_id
+		JOIN norms na ON na.item_id = a.item_id AND na.norm > 0
+		JOIN norms nb ON nb.item_id = b.item_id AND nb.norm > 0
+		GROUP BY a.item_type, a.item_id, b.item_type, b.item_id, na.norm, nb.norm
+		HAVING COUNT(*) >= $1 AND SUM(a.score * b.score) > 0
+	)
+	SELECT item_type, item_id, similar_type, similar_id, score, common_raters
+	FROM (
+		SELECT *, ROW_NUMBER() OVER (PARTITION BY item_id ORDER BY score DESC, similar_id) AS position
+		FROM similarities
+	) ranked
+	WHERE position <= $3`
+
+	result, err := customTx.Exec(query, minCommonRaters, similarityShrinkage, maxSimilarItems)
+	if err != nil {
+		customTx.Rollback()
+		return models.ComputedSimilarities{}, err
+	}
+	var computed models.ComputedSimilarities
+	if computed.Similarities, err = result.RowsAffected(); err != nil {
+		customTx.Rollback()
+		return models.ComputedSimilarities{}, err
+	}
+	if err := customTx.Get(&computed.Items, `SELECT COUNT(DISTINCT item_id) FROM item_similarities`); err != nil {
+		customTx.Rollback()
+		return models.ComputedSimilarities{}, err
+	}
+
+	if err := customTx.Commit(); err != nil {
+		return models.ComputedSimilarities{}, err
+	}
+	return computed, nil
+}
+
+// itemsSQL returns visible edu centers and courses with their rating aggregates
+func (r *RecommendationRepository) itemsSQL() string {
+	return fmt.Sprintf(`SELECT 'edu_center' AS type, e.id, e.name, e.id AS edu_center_id, e.location, %s
+		FROM edu_centers e
+		WHERE e.deleted_at IS NULL AND e.hidden_at IS NULL
+		UNION ALL
+		SELECT 'course', c.id, c.name, c.edu_center_id, e.location, %s
+		FROM courses c
+		JOIN edu_centers e ON e.id = c.edu_center_id AND e.deleted_at IS NULL AND e.hidden_at IS NULL
+		WHERE c.deleted_at IS NULL AND c.hidden_at IS NULL`,
+		r.ratingPrior.ratingStatsSQL(models.EduCenterTarget, "e"), r.ratingPrior.ratingStatsSQL(models.CourseTarget, "c"))
+}
+
+func (r *RecommendationRepository) GetSimilarCourses(courseID uuid.UUID, limit int) ([]models.Recommendation, error) {
+	var exists bool
+	if err := r.db.Get(&exists, `SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1 AND deleted_at IS NULL)`, courseID); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, custom_errors.ErrCourseNotFound
+	}
+
+	query := fmt.Sprintf(`WITH items AS (%s)
+	SELECT i.type, i.id, i.name, i.edu_center_id, i.location, i.rating, i.rating_count, s.score, '%s' AS reason
+	FROM item_similarities s
+	JOIN items i ON i.id = s.similar_id
+	WHERE s.item_id = $1 AND s.similar_type = 'course'
+	ORDER BY s.score DESC, i.id
+	LIMIT $2`, r.itemsSQL(), models.RecommendationSimilar)
+
+	recommendations := []models.Recommendation{}
+	if err := r.db.Select(&recommendations, query, courseID, limit); err != nil {
+		return nil, err
+	}
+	return recommendations, nil
+}
+
+// middle of rating scale, items rated above it are liked and the ones below are disliked
+const neutralScore = 3
+
+// GetUserRecommendations ranks items similar to ones user rated by sum of similarities weighted by how much
+// user liked each rated item. Items user already rated are left out.
+func (r *RecommendationRepository) GetUserRecommendations(userID uuid.UUID, limit int) ([]models.Recommendation, error) {
+	query := fmt.Sprintf(`WITH items AS (%s), rated AS (
+		SELECT COALESCE(r.edu_center_id, r.course_id) AS item_id, r.score - %d AS liking
+		FROM ratings r
+		WHERE r.owner_id = $1 AND r.hidden_at IS NULL
+	)
+	SELECT i.type, i.id, i.name, i.edu_center_id, i.location, i.rating, i.rating_count, scored.score, '%s' AS reason
+	FROM (
+		SELECT s.similar_id, ROUND(SUM(s.score * rated.liking)::numeric, 4) AS score
+		FROM rated
+		JOIN item_similarities s ON s.item_id = rated.item_id
+		WHERE s.similar_id NOT IN (SELECT item_id FROM rated)
+		GROUP BY s.similar_id
+		HAVING SUM(s.score * rated.liking) > 0
+	) scored
+	JOIN items i ON i.id = scored.similar_id
+	ORDER BY scored.score DESC, i.id
+	LIMIT $2`, r.itemsSQL(), neutralScore, models.RecommendationSimilar)
+
+	recommendations := []models.Recommendation{}
+	if err := r.db.Select(&recommendations, query, userID, limit); err != nil {
+		return nil, err
+	}
+	return recommendations, nil
+}
+
+// GetPopularRecommendations returns the best rated items within radius in km around location,
+// or all over when location is not given
+func (r *RecommendationRepository) GetPopularRecommendations(location *models.Point, radius float64, limit int) ([]models.Recommendation, error) {
+	var (
+		args      queryArgs
+		condition = "i.rating_count > 0"
+	)
+	if location != nil {
+		distance := distanceSQL("i.location", args.add(location.Latitude)+"::float8", args.add(location.Longitude)+"::float8")
+		condition += fmt.Sprintf(" AND i.location IS NOT NULL AND %s <= %s", distance, args.add(radius))
+	}
+	query := fmt.Sprintf(`WITH items AS (%s)
+	SELECT i.type, i.id, i.name, i.edu_center_id, i.location, i.rating, i.rating_count,
+	i.weighted_rating AS score, '%s' AS reason
+	FROM items i
+	WHERE %s
+	ORDER BY i.weighted_rating DESC, i.rating_count DESC, i.id
+	LIMIT %s`, r.itemsSQL(), models.RecommendationPopular, condition, args.add(limit))
+
+	recommendations := []models.Recommendation{}
+	if err := r.db.Select(&recommendations, query, args.values...); err != nil {
+		return nil, err
+	}
+	return recommendations, nil
+}
@@ -0,0 +1,206 @@
+package repositories
+
+import (
+	"database/sql"
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ReviewRepositoryInterface interface {
+	SaveReview(target models.RatingTarget, targetID uuid.UUID, userID uuid.UUID, review models.ReviewDto) (models.Review, error)
+	GetReviews(target models.RatingTarget, targetID uuid.UUID, params models.ReviewQuery) (models.AllReviews, error)
+	GetReviewAuthor(reviewID uuid.UUID) (uuid.UUID, error)
+	VoteHelpful(reviewID uuid.UUID, userID uuid.UUID) error
+	DeleteHelpfulVote(reviewID uuid.UUID, userID uuid.UUID) error
+}
+
+type ReviewRepository struct {
+	db *sqlx.DB
+}
+
+func NewReviewRepository(db *sqlx.DB) ReviewRepositoryInterface {
+	return &ReviewRepository{
+		db: db,
+	}
+}
+
+// ratingTargetColumns maps rating targets to their column in ratings table
+var ratingTargetColumns = map[models.RatingTarget]string{
+	models.EduCenterTarget: "edu_center_id",
+	models.CourseTarget:    "course_id",
+}
+
+// reviewSortColumns maps sort keys of listing to sort expression and its sql type
+var reviewSortColumns = map[string][2]string{
+	models.ReviewSortNewest:  {"created_at", "timestamptz"},
+	models.ReviewSortHelpful: {"helpful_count", "int8"},
+}
+
+// SaveReview attaches review to rating of user or edits existing one
+func (r *ReviewRepository) SaveReview(target models.RatingTarget, targetID uuid.UUID, userID uuid.UUID, review models.ReviewDto) (models.Review, error) {
+	query := fmt.Sprintf(`WITH saved AS (
+		INSERT INTO reviews (rating_id, title, body, pros, cons)
+		SELECT id, $3, $4, $5, $6 FROM ratings WHERE %s = $1 AND owner_id = $2
+		ON CONFLICT (rating_id) DO UPDATE
+		SET title = EXCLUDED.title, body = EXCLUDED.body, pros = EXCLUDED.pros, cons = EXCLUDED.cons, updated_at = CURRENT_TIMESTAMP
+		RETURNING id, rating_id, title, body, pros, cons, created_at, updated_at
+	)
+	SELECT s.id, s.rating_id, r.score, s.title, s.body, s.pros, s.cons, s.created_at, s.updated_at,
+	(SELECT COUNT(*) FROM review_votes WHERE review_id = s.id) AS helpful_count,
+	u.id AS reviewer_id, COALESCE(u.first_name, '') AS first_name, COALESCE(u.last_name, '') AS last_name,
+	COALESCE(u.username, '') AS username, COALESCE(u.avatar, '') AS avatar
+	FROM saved s
+	JOIN ratings r ON r.id = s.rating_id
+	JOIN users u ON u.id = r.owner_id`, ratingTargetColumns[target])
+
+	var savedReview models.Review
+	err := r.db.QueryRow(query, targetID, userID, review.Title, review.Body, review.Pros, review.Cons).Scan(
+		&savedReview.ID,
+		&savedReview.RatingID,
+		&savedReview.Score,
+		&savedReview.Title,
+		&savedReview.Body,
+		&savedReview.Pros,
+		&savedReview.Cons,
+		&savedReview.CreatedAt,
+		&savedReview.UpdatedAt,
+		&savedReview.HelpfulCount,
+		&savedReview.Reviewer.ID,
+		&savedReview.Reviewer.FirstName,
+		&savedReview.Reviewer.LastName,
+		&savedReview.Reviewer.Username,
+		&savedReview.Reviewer.Avatar,
+	)
+	if err != nil {
+		//review can be written only for existing rating
+		if err == sql.ErrNoRows {
+			err = custom_errors.ErrRatingNotFound
+		}
+		return models.Review{}, err
+	}
+
+	return savedReview, nil
+}
+
+func (r *ReviewRepository) GetReviews(target models.RatingTarget, targetID uuid.UUID, params models.ReviewQuery) (models.AllReviews, error) {
+	var args queryArgs
+	cte := fmt.Sprintf(`WITH target_reviews AS (
+		SELECT v.id, v.rating_id, r.score, v.title, v.body, v.pros, v.cons, v.created_at, v.updated_at,
+		(SELECT COUNT(*) FROM review_votes rv WHERE rv.review_id = v.id) AS helpful_count,
+		u.id AS reviewer_id, COALESCE(u.first_name, '') AS first_name, COALESCE(u.last_name, '') AS last_name,
+		COALESCE(u.username, '') AS username, COALESCE(u.avatar, '') AS avatar
+		FROM reviews v
+		JOIN ratings r ON r.id = v.rating_id
+		JOIN users u ON u.id = r.owner_id
+		WHERE r.%s = %s AND r.hidden_at IS NULL
+	)`, ratingTargetColumns[target], args.add(targetID))
+
+	var allReviews models.AllReviews
+	if err := r.db.Get(&allReviews.Count, cte+` SELECT COUNT(*) FROM target_reviews`, args.values...); err != nil {
+		return models.AllReviews{}, err
+	}
+
+	var conditions []string
+	sortColumn := reviewSortColumns[params.SortBy]
+	if params.Cursor != "" {
+		condition, err := keysetCondition(&args, params.Cursor, sortColumn[0], sortColumn[1], params.Order)
+		if err != nil {
+			return models.AllReviews{}, err
+		}
+		conditions = append(conditions, condition)
+	}
+
+	// one more row is fetched to know whether next page exists
+	query := fmt.Sprintf(`%s
+	SELECT id, rating_id, score, title, body, pros, cons, created_at, updated_at, helpful_count,
+	reviewer_id, first_name, last_name, username, avatar, (%s)::text AS sort_value
+	FROM target_reviews
+	WHERE %s
+	ORDER BY %s %s, id %s
+	LIMIT %s OFFSET %s`,
+		cte, sortColumn[0], joinConditions(conditions), sortColumn[0], params.Order, params.Order,
+		args.add(params.Limit+1), args.add(pageOffset(params.PageQuery)))
+
+	rows, err := r.db.Query(query, args.values...)
+	if err != nil {
+		return models.AllReviews{}, err
+	}
+	defer rows.Close()
+
+	var sortValue, lastSortValue string
+	for rows.Next() {
+		var review models.Review
+		err := rows.Scan(
+			&review.ID,
+			&review.RatingID,
+			&review.Score,
+			&review.Title,
+			&review.Body,
+			&review.Pros,
+			&review.Cons,
+			&review.CreatedAt,
+			&review.UpdatedAt,
+			&review.HelpfulCount,
+			&review.Reviewer.ID,
+			&review.Reviewer.FirstName,
+			&review.Reviewer.LastName,
+			&review.Reviewer.Username,
+			&review.Reviewer.Avatar,
+			&sortValue,
+		)
+		if err != nil {
+			return models.AllReviews{}, err
+		}
+
+		if len(allReviews.Reviews) == params.Limit {
+			//there is next page
+			last := allReviews.Reviews[params.Limit-1]
+			allReviews.NextCursor = encodeCursor(lastSortValue, last.ID)
+			break
+		}
+		lastSortValue = sortValue
+		allReviews.Reviews = append(allReviews.Reviews, review)
+	}
+
+	if err := rows.Err(); err != nil {
+		return models.AllReviews{}, err
+	}
+
+	return allReviews, nil
+}
+
+func (r *ReviewRepository) GetReviewAuthor(reviewID uuid.UUID) (uuid.UUID, error) {
+	var authorID uuid.UUID
+	query := `SELECT r.owner_id FROM reviews v JOIN ratings r ON r.id = v.rating_id WHERE v.id = $1`
+	if err := r.db.Get(&authorID, query, reviewID); err != nil {
+		if err == sql.ErrNoRows {
+			err = custom_errors.ErrReviewNotFound
+		}
+		return uuid.Nil, err
+	}
+	return authorID, nil
+}
+
+func (r *ReviewRepository) VoteHelpful(reviewID uuid.UUID, userID uuid.UUID) error {
+	_, err := r.db.Exec(`INSERT INTO review_votes (review_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, reviewID, userID)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			err = custom_errors.ErrReviewNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *ReviewRepository) DeleteHelpfulVote(reviewID uuid.UUID, userID uuid.UUID) error {
+	_, err := r.db.Exec(`DELETE FROM review_votes WHERE review_id = $1 AND user_id = $2`, reviewID, userID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
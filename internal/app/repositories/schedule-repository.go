@@ -0,0 +1,237 @@
+package repositories
+
+import (
+	"database/sql"
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
+	database "edumatch/pkg/db"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ScheduleRepositoryInterface interface {
+	GetCourseSchedules(courseID uuid.UUID) ([]models.CourseSchedule, error)
+	GetCourseSchedule(courseID uuid.UUID, scheduleID uuid.UUID) (models.CourseSchedule, error)
+	LockCourseCenter(tx database.Transaction, courseID uuid.UUID) (models.CourseCenter, error)
+	FindConflicts(tx database.Transaction, center models.CourseCenter, schedule models.CourseScheduleDto) ([]models.ScheduleConflict, error)
+	CreateSchedule(tx database.Transaction, schedule models.CourseScheduleDto) (uuid.UUID, error)
+	UpdateSchedule(tx database.Transaction, schedule models.CourseScheduleDto) error
+	DeleteSchedule(tx database.Transaction, courseID uuid.UUID, scheduleID uuid.UUID) error
+	BeginTransaction() (database.Transaction, error)
+}
+
+type ScheduleRepository struct {
+	db *sqlx.DB
+}
+
+func NewScheduleRepository(db *sqlx.DB) ScheduleRepositoryInterface {
+	return &ScheduleRepository{
+		db: db,
+	}
+}
+
+const scheduleColumns = `g.id, g.course_id, g.name, to_char(g.start_date, 'YYYY-MM-DD') AS start_date,
+	COALESCE(to_char(g.end_date, 'YYYY-MM-DD'), '') AS end_date, g.room, g.teacher, g.language, g.created_at, g.updated_at`
+
+const slotColumns = `s.id, s.schedule_id, s.day_of_week,
+	to_char(s.start_time, 'HH24:MI') AS start_time, to_char(s.end_time, 'HH24:MI') AS end_time`
+
+// GetCourseSchedules returns groups of visible course ordered by start date, each with its slots
+func (r *ScheduleRepository) GetCourseSchedules(courseID uuid.UUID) ([]models.CourseSchedule, error) {
+	var exists bool
+	if err := r.db.Get(&exists, `SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1 AND deleted_at IS NULL AND hidden_at IS NULL)`, courseID); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, custom_errors.ErrCourseNotFound
+	}
+
+	schedules := []models.CourseSchedule{}
+	query := `SELECT ` + scheduleColumns + ` FROM course_schedules g WHERE g.course_id = $1 ORDER BY g.start_date, g.created_at, g.id`
+	if err := r.db.Select(&schedules, query, courseID); err != nil {
+		return nil, err
+	}
+	if err := r.attachSlots(schedules); err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (r *ScheduleRepository) GetCourseSchedule(courseID uuid.UUID, scheduleID uuid.UUID) (models.CourseSchedule, error) {
+	var schedule models.CourseSchedule
+	query := `SELECT ` + scheduleColumns + ` FROM course_schedules g WHERE g.id = $1 AND g.course_id = $2`
+	if err := r.db.Get(&schedule, query, scheduleID, courseID); err != nil {
+		if err == sql.ErrNoRows {
+			err = custom_errors.ErrScheduleNotFound
+		}
+		return models.CourseSchedule{}, err
+	}
+	schedules := []models.CourseSchedule{schedule}
+	if err := r.attachSlots(schedules); err != nil {
+		return models.CourseSchedule{}, err
+	}
+	return schedules[0], nil
+}
+
+func (r *ScheduleRepository) attachSlots(schedules []models.CourseSchedule) error {
+	ids := make([]uuid.UUID, 0, len(schedules))
+	positions := map[uuid.UUID]int{}
+	for i := range schedules {
+		schedules[i].Slots = []models.ScheduleSlot{}
+		ids = append(ids, schedules[i].ID)
+		positions[schedules[i].ID] = i
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var slots []models.ScheduleSlot
+	query := `SELECT ` + slotColumns + ` FROM schedule_slots s WHERE s.schedule_id = ANY($1) ORDER BY s.day_of_week, s.start_time, s.position`
+	if err := r.db.Select(&slots, query, pq.Array(ids)); err != nil {
+		return err
+	}
+	for _, slot := range slots {
+		i := positions[slot.ScheduleID]
+		schedules[i].Slots = append(schedules[i].Slots, slot)
+	}
+	return nil
+}
+
+// LockCourseCenter returns center of course and locks it till the end of transaction,
+// so schedules of the same center are checked for conflicts and saved one by one
+func (r *ScheduleRepository) LockCourseCenter(tx database.Transaction, courseID uuid.UUID) (models.CourseCenter, error) {
+	var center models.CourseCenter
+	query := `SELECT e.id AS edu_center_id, e.owner_id, COALESCE(c.teacher, '') AS teacher
+	FROM courses c
+	JOIN edu_centers e ON e.id = c.edu_center_id
+	WHERE c.id = $1 AND c.deleted_at IS NULL AND e.deleted_at IS NULL
+	FOR UPDATE OF e`
+	if err := tx.Get(&center, query, courseID); err != nil {
+		if err == sql.ErrNoRows {
+			err = custom_errors.ErrCourseNotFound
+		}
+		return models.CourseCenter{}, err
+	}
+	return center, nil
+}
+
+// FindConflicts returns slots of other groups of the center in the same room or with the same teacher
+// overlapping slots of schedule in time while their periods of dates overlap
+func (r *ScheduleRepository) FindConflicts(tx database.Transaction, center models.CourseCenter, schedule models.CourseScheduleDto) ([]models.ScheduleConflict, error) {
+	teacher := schedule.Teacher
+	if teacher == "" {
+		teacher = center.Teacher
+	}
+	days, starts, ends := slotArrays(schedule.Slots)
+
+	query := `WITH new_slots AS (
+		SELECT * FROM unnest($1::smallint[], $2::time[], $3::time[]) AS n(day_of_week, start_time, end_time)
+	)
+	SELECT g.name AS schedule_name, c.name AS course_name, ($4 <> '' AND g.room = $4) AS same_room,
+	s.day_of_week, to_char(s.start_time, 'HH24:MI') AS start_time, to_char(s.end_time, 'HH24:MI') AS end_time
+	FROM course_schedules g
+	JOIN courses c ON c.id = g.course_id AND c.deleted_at IS NULL
+	JOIN schedule_slots s ON s.schedule_id = g.id
+	JOIN new_slots n ON n.day_of_week = s.day_of_week AND n.start_time < s.end_time AND s.start_time < n.end_time
+	WHERE c.edu_center_id = $6 AND g.id <> $7
+	AND g.start_date <= COALESCE(NULLIF($9, '')::date, 'infinity') AND $8::date <= COALESCE(g.end_date, 'infinity')
+	AND (($4 <> '' AND g.room = $4) OR ($5 <> '' AND COALESCE(NULLIF(g.teacher, ''), c.teacher) = $5))
+	ORDER BY s.day_of_week, s.start_time`
+	conflicts := []models.ScheduleConflict{}
+	err := tx.Select(&conflicts, query, days, starts, ends, schedule.Room, teacher,
+		center.EduCenterID, schedule.ID, schedule.StartDate, schedule.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	return conflicts, nil
+}
+
+func (r *ScheduleRepository) CreateSchedule(tx database.Transaction, schedule models.CourseScheduleDto) (uuid.UUID, error) {
+	var id uuid.UUID
+	query := `INSERT INTO course_schedules (course_id, name, start_date, end_date, room, teacher, language)
+	VALUES ($1, $2, $3::date, NULLIF($4, '')::date, $5, $6, $7)
+	RETURNING id`
+	err := tx.Get(&id, query, schedule.CourseID, schedule.Name, schedule.StartDate, schedule.EndDate,
+		schedule.Room, schedule.Teacher, schedule.Language)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if err := saveSlots(tx, id, schedule.Slots); err != nil {
+		return uuid.Nil, err
+	}
+	return id, nil
+}
+
+// UpdateSchedule replaces group and all its slots
+func (r *ScheduleRepository) UpdateSchedule(tx database.Transaction, schedule models.CourseScheduleDto) error {
+	query := `UPDATE course_schedules SET name = $3, start_date = $4::date, end_date = NULLIF($5, '')::date,
+	room = $6, teacher = $7, language = $8, updated_at = CURRENT_TIMESTAMP
+	WHERE id = $1 AND course_id = $2`
+	result, err := tx.Exec(query, schedule.ID, schedule.CourseID, schedule.Name, schedule.StartDate, schedule.EndDate,
+		schedule.Room, schedule.Teacher, schedule.Language)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return custom_errors.ErrScheduleNotFound
+	}
+
+	if _, err := tx.Exec(`DELETE FROM schedule_slots WHERE schedule_id = $1`, schedule.ID); err != nil {
+		return err
+	}
+	return saveSlots(tx, schedule.ID, schedule.Slots)
+}
+
+func (r *ScheduleRepository) DeleteSchedule(tx database.Transaction, courseID uuid.UUID, scheduleID uuid.UUID) error {
+	result, err := tx.Exec(`DELETE FROM course_schedules WHERE id = $1 AND course_id = $2`, scheduleID, courseID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return custom_errors.ErrScheduleNotFound
+	}
+	return nil
+}
+
+func (r *ScheduleRepository) BeginTransaction() (database.Transaction, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	return &database.CustomTx{Tx: tx}, nil
+}
+
+func saveSlots(tx database.Transaction, scheduleID uuid.UUID, slots []models.ScheduleSlotDto) error {
+	days, starts, ends := slotArrays(slots)
+	//position keeps calendar event of slot when its times change
+	query := `INSERT INTO schedule_slots (schedule_id, day_of_week, start_time, end_time, position)
+	SELECT $1, n.day_of_week, n.start_time, n.end_time, n.position - 1
+	FROM unnest($2::smallint[], $3::time[], $4::time[]) WITH ORDINALITY AS n(day_of_week, start_time, end_time, position)`
+	_, err := tx.Exec(query, scheduleID, days, starts, ends)
+	return err
+}
+
+// slotArrays splits slots into arrays of their fields to pass them with unnest
+func slotArrays(slots []models.ScheduleSlotDto) (pq.Int64Array, pq.StringArray, pq.StringArray) {
+	var (
+		days   pq.Int64Array
+		starts pq.StringArray
+		ends   pq.StringArray
+	)
+	for _, slot := range slots {
+		days = append(days, int64(*slot.DayOfWeek))
+		starts = append(starts, slot.StartTime)
+		ends = append(ends, slot.EndTime)
+	}
+	return days, starts, ends
+}
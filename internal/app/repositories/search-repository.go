@@ -0,0 +1,231 @@
+package repositories
+
+import (
+	"edumatch/internal/app/models"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type SearchRepositoryInterface interface {
+	Search(params models.SearchQuery, fuzzy bool) (models.SearchResults, error)
+	Suggest(prefix string, limit int) ([]models.Suggestion, error)
+}
+
+type SearchRepository struct {
+	db *sqlx.DB
+}
+
+func NewSearchRepository(db *sqlx.DB) SearchRepositoryInterface {
+	return &SearchRepository{
+		db: db,
+	}
+}
+
+// ts_headline returns up to two short fragments around matches instead of the whole field
+const searchHeadlineOptions = `StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5`
+
+// the second highlighted field of results, after name and before description
+var searchDetailFields = map[string]string{
+	models.SearchTypeEduCenter: "address",
+	models.SearchTypeCourse:    "teacher",
+}
+
+// Search finds visible centers and courses whose words match text and ranks them by ts_rank_cd.
+// Fuzzy search matches names, addresses and teachers by trigram word similarity instead.
+func (r *SearchRepository) Search(params models.SearchQuery, fuzzy bool) (models.SearchResults, error) {
+	var (
+		args             queryArgs
+		text             = args.add(params.Text) + "::text"
+		tsQuery          = fmt.Sprintf("websearch_to_tsquery('simple', %s)", text)
+		centerConditions = []string{"e.deleted_at IS NULL", "e.hidden_at IS NULL"}
+		courseConditions = []string{"c.deleted_at IS NULL", "c.hidden_at IS NULL", "e.deleted_at IS NULL", "e.hidden_at IS NULL"}
+		centerRank       string
+		courseRank       string
+	)
+	if fuzzy {
+		centerConditions = append(centerConditions, fmt.Sprintf("(%[1]s <%% e.name OR %[1]s <%% e.address)", text))
+		courseConditions = append(courseConditions, fmt.Sprintf("(%[1]s <%% c.name OR %[1]s <%% c.teacher)", text))
+		centerRank = fmt.Sprintf("GREATEST(word_similarity(%[1]s, e.name), word_similarity(%[1]s, COALESCE(e.address, '')))", text)
+		courseRank = fmt.Sprintf("GREATEST(word_similarity(%[1]s, c.name), word_similarity(%[1]s, COALESCE(c.teacher, '')))", text)
+	} else {
+		centerConditions = append(centerConditions, "e.search_vector @@ "+tsQuery)
+		courseConditions = append(courseConditions, "c.search_vector @@ "+tsQuery)
+		centerRank = fmt.Sprintf("ts_rank_cd(e.search_vector, %s)", tsQuery)
+		courseRank = fmt.Sprintf("ts_rank_cd(c.search_vector, %s)", tsQuery)
+	}
+	if params.MinRating > 0 {
+		minRating := args.add(params.MinRating)
+		centerConditions = append(centerConditions, "e.rating_avg >= "+minRating)
+		courseConditions = append(courseConditions, "c.rating_avg >= "+minRating)
+	}
+	//courses are placed at location of their center
+	distance := "NULL::float8"
+	if params.Latitude != nil && params.Longitude != nil {
+		distance = distanceSQL("e.location", args.add(*params.Latitude)+"::float8", args.add(*params.Longitude)+"::float8")
+		if params.Distance > 0 {
+			condition := fmt.Sprintf("e.location IS NOT NULL AND %s <= %s", distance, args.add(params.Distance))
+			centerConditions = append(centerConditions, condition)
+			courseConditions = append(courseConditions, condition)
+		}
+	}
+
+	var parts []string
+	if params.Type != models.SearchTypeCourse {
+		parts = append(parts, fmt.Sprintf(`SELECT 'edu_center' AS type, e.id, e.name, e.id AS edu_center_id, e.location,
+		e.rating_avg AS rating, %s AS distance, %s AS rank,
+		COALESCE(e.address, '') AS detail,
+		regexp_replace(COALESCE(e.html_description, ''), '<[^>]*>', ' ', 'g') AS description
+		FROM edu_centers e
+		WHERE %s`, distance, centerRank, joinConditions(centerConditions)))
+	}
+	if params.Type != models.SearchTypeEduCenter {
+		parts = append(parts, fmt.Sprintf(`SELECT 'course' AS type, c.id, c.name, c.edu_center_id, e.location,
+		c.rating_avg AS rating, %s AS distance, %s AS rank,
+		COALESCE(c.teacher, '') AS detail,
+		COALESCE(c.description, '') AS description
+		FROM courses c
+		JOIN edu_centers e ON e.id = c.edu_center_id
+		WHERE %s`, distance, courseRank, joinConditions(courseConditions)))
+	}
+	matches := strings.Join(parts, "\n\tUNION ALL\n\t")
+
+	results := models.SearchResults{Fuzzy: fuzzy, Results: []models.SearchResult{}}
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM (%s) matches`, matches)
+	if err := r.db.Get(&results.Count, countQuery, args.values...); err != nil {
+		return models.SearchResults{}, err
+	}
+	if results.Count == 0 {
+		return results, nil
+	}
+
+	pageCondition := "TRUE"
+	if params.Cursor != "" {
+		condition, err := keysetCondition(&args, params.Cursor, "rank", "real", params.Order)
+		if err != nil {
+			return models.SearchResults{}, err
+		}
+		pageCondition = condition
+	}
+
+	//fuzzy matches aren't words of search vector, so ts_headline finds nothing in them
+	headlines := fmt.Sprintf(`ts_headline('simple', m.name, %[1]s, '%[2]s') AS name_highlight,
+	ts_headline('simple', m.detail, %[1]s, '%[2]s') AS detail_highlight,
+	ts_headline('simple', m.description, %[1]s, '%[2]s') AS description_highlight`, tsQuery, searchHeadlineOptions)
+	if fuzzy {
+		headlines = fmt.Sprintf(`%s AS name_highlight, %s AS detail_highlight, '' AS description_highlight`,
+			fuzzyHeadlineSQL("m.name", text), fuzzyHeadlineSQL("m.detail", text))
+	}
+
+	//fragments are highlighted only for rows of the page, one more row is fetched to know whether next page exists
+	query := fmt.Sprintf(`SELECT m.type, m.id, m.name, m.edu_center_id, m.location, m.rating, m.distance, m.rank,
+	m.rank::text AS sort_value, %[2]s
+	FROM (
+		SELECT * FROM (%[1]s) matches
+		WHERE %[3]s
+		ORDER BY rank %[4]s, id %[4]s
+		LIMIT %[5]s OFFSET %[6]s
+	) m
+	ORDER BY m.rank %[4]s, m.id %[4]s`,
+		matches, headlines, pageCondition, params.Order, args.add(params.Limit+1), args.add(pageOffset(params.PageQuery)))
+
+	var rows []struct {
+		models.SearchResult
+		SortValue            string `db:"sort_value"`
+		NameHighlight        string `db:"name_highlight"`
+		DetailHighlight      string `db:"detail_highlight"`
+		DescriptionHighlight string `db:"description_highlight"`
+	}
+	if err := r.db.Select(&rows, query, args.values...); err != nil {
+		return models.SearchResults{}, err
+	}
+	if len(rows) > params.Limit {
+		//there is next page
+		last := rows[params.Limit-1]
+		results.NextCursor = encodeCursor(last.SortValue, last.ID)
+		rows = rows[:params.Limit]
+	}
+	for _, row := range rows {
+		result := row.SearchResult
+		result.Highlights = map[string]string{}
+		fragments := map[string]string{
+			"name":                       row.NameHighlight,
+			searchDetailFields[row.Type]: row.DetailHighlight,
+			"description":                row.DescriptionHighlight,
+		}
+		//headline of field without matches is just its beginning
+		for field, fragment := range fragments {
+			if strings.Contains(fragment, "<mark>") {
+				result.Highlights[field] = fragment
+			}
+		}
+		results.Results = append(results.Results, result)
+	}
+	return results, nil
+}
+
+// fuzzyHeadlineSQL returns column with its words trigram similar to a word of text wrapped in <mark> tags,
+// the same way ts_headline marks exact matches
+func fuzzyHeadlineSQL(column, text string) string {
+	return fmt.Sprintf(`COALESCE((SELECT string_agg(
+		CASE WHEN w.parts[1] ~ '\w' AND EXISTS (
+			SELECT 1 FROM regexp_split_to_table(lower(%[2]s), '\W+') q(word)
+			WHERE q.word <> '' AND q.word %% lower(w.parts[1])
+		) THEN '<mark>' || w.parts[1] || '</mark>' ELSE w.parts[1] END, '' ORDER BY w.n)
+		FROM regexp_matches(%[1]s, '\w+|\W+', 'g') WITH ORDINALITY AS w(parts, n)), '')`, column, text)
+}
+
+// shorter prefixes are completed only from the start of text, trigram index can't look up inner words by them
+const minInnerWordPrefix = 3
+
+// Suggest completes prefix with names of visible centers and courses and with teachers, up to limit of each type.
+// Suggestions starting with prefix come first, then better rated centers and courses and teachers of more courses.
+func (r *SearchRepository) Suggest(prefix string, limit int) ([]models.Suggestion, error) {
+	var args queryArgs
+	startsWith := fmt.Sprintf("lower(%s::text)", args.add(escapeLike(prefix)+"%"))
+	innerWord := "FALSE"
+	if utf8.RuneCountInString(prefix) >= minInnerWordPrefix {
+		innerWord = args.add("% "+escapeLike(prefix)+"%") + "::text"
+	}
+	limitArg := args.add(limit)
+	match := func(column string) string {
+		return fmt.Sprintf("CASE WHEN lower(%s) LIKE %s THEN 0 ELSE 1 END", column, startsWith)
+	}
+	condition := func(column string) string {
+		if innerWord == "FALSE" {
+			return fmt.Sprintf("lower(%s) LIKE %s", column, startsWith)
+		}
+		return fmt.Sprintf("(lower(%[1]s) LIKE %[2]s OR %[1]s ILIKE %[3]s)", column, startsWith, innerWord)
+	}
+
+	query := fmt.Sprintf(`(SELECT 'edu_center' AS type, e.name AS text, e.id, %[1]s AS match
+	FROM edu_centers e
+	WHERE e.deleted_at IS NULL AND e.hidden_at IS NULL AND %[2]s
+	ORDER BY match, e.rating_avg DESC, length(e.name), e.id
+	LIMIT %[7]s)
+	UNION ALL
+	(SELECT 'course', c.name, c.id, %[3]s
+	FROM courses c
+	JOIN edu_centers e ON e.id = c.edu_center_id AND e.deleted_at IS NULL AND e.hidden_at IS NULL
+	WHERE c.deleted_at IS NULL AND c.hidden_at IS NULL AND %[4]s
+	ORDER BY 4, c.rating_avg DESC, length(c.name), c.id
+	LIMIT %[7]s)
+	UNION ALL
+	(SELECT 'teacher', c.teacher, NULL::uuid, MIN(%[5]s)
+	FROM courses c
+	JOIN edu_centers e ON e.id = c.edu_center_id AND e.deleted_at IS NULL AND e.hidden_at IS NULL
+	WHERE c.deleted_at IS NULL AND c.hidden_at IS NULL AND %[6]s
+	GROUP BY c.teacher
+	ORDER BY 4, COUNT(*) DESC, c.teacher
+	LIMIT %[7]s)`,
+		match("e.name"), condition("e.name"), match("c.name"), condition("c.name"),
+		match("c.teacher"), condition("c.teacher"), limitArg)
+
+	suggestions := []models.Suggestion{}
+	if err := r.db.Select(&suggestions, query, args.values...); err != nil {
+		return nil, err
+	}
+	return suggestions, nil
+}
@@ -0,0 +1,188 @@
+package repositories
+
+import (
+	"database/sql"
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
+	database "edumatch/pkg/db"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type TokenRepositoryInterface interface {
+	CreateSession(tx database.Transaction, session models.Session) error
+	CreateRefreshToken(tx database.Transaction, token models.RefreshToken) error
+	LockRefreshToken(tx database.Transaction, tokenID uuid.UUID) (models.RefreshToken, error)
+	RotateRefreshToken(tx database.Transaction, token models.RefreshToken, ip string) error
+	TerminateSession(tx database.Transaction, sessionID uuid.UUID) error
+	TerminateUserSession(userID uuid.UUID, sessionID uuid.UUID) error
+	TerminateUserSessions(tx database.Transaction, userID uuid.UUID, exceptSessionID uuid.UUID) error
+	GetActiveSession(userID uuid.UUID, sessionID uuid.UUID) (models.Session, error)
+	GetUserSessions(userID uuid.UUID) ([]models.Session, error)
+	TouchSession(sessionID uuid.UUID, ip string) error
+	CreatePasswordReset(tx database.Transaction, reset models.PasswordReset) error
+	UsePasswordReset(tx database.Transaction, tokenHash string) (uuid.UUID, error)
+	BeginTransaction() (database.Transaction, error)
+}
+
+type TokenRepository struct {
+	db *sqlx.DB
+}
+
+func NewTokenRepository(db *sqlx.DB) TokenRepositoryInterface {
+	return &TokenRepository{
+		db: db,
+	}
+}
+
+const refreshTokenColumns = `id, session_id, user_id, issued_at, expires_at, rotated_at, revoked_at`
+
+const sessionColumns = `id, user_id, device, ip, created_at, last_seen_at, expires_at, terminated_at`
+
+func (r *TokenRepository) CreateSession(tx database.Transaction, session models.Session) error {
+	_, err := tx.Exec(`INSERT INTO sessions (id, user_id, device, ip, created_at, last_seen_at, expires_at)
+	VALUES ($1, $2, $3, $4, $5, $5, $6)`, session.ID, session.UserID, session.Device, session.IP, session.CreatedAt, session.ExpiresAt)
+	return err
+}
+
+func (r *TokenRepository) CreateRefreshToken(tx database.Transaction, token models.RefreshToken) error {
+	_, err := tx.Exec(`INSERT INTO refresh_tokens (id, session_id, user_id, issued_at, expires_at)
+	VALUES ($1, $2, $3, $4, $5)`, token.ID, token.SessionID, token.UserID, token.IssuedAt, token.ExpiresAt)
+	if err != nil {
+		return err
+	}
+	//session lasts as long as its newest refresh token
+	_, err = tx.Exec(`UPDATE sessions SET expires_at = $2 WHERE id = $1`, token.SessionID, token.ExpiresAt)
+	return err
+}
+
+// LockRefreshToken returns record of refresh token and locks it till the end of transaction,
+// so the same token can't be rotated twice concurrently
+func (r *TokenRepository) LockRefreshToken(tx database.Transaction, tokenID uuid.UUID) (models.RefreshToken, error) {
+	var token models.RefreshToken
+	if err := tx.Get(&token, `SELECT `+refreshTokenColumns+` FROM refresh_tokens WHERE id = $1 FOR UPDATE`, tokenID); err != nil {
+		//token isn't issued by us or its user is deleted
+		if err == sql.ErrNoRows {
+			err = custom_errors.ErrInvalidToken
+		}
+		return models.RefreshToken{}, err
+	}
+	return token, nil
+}
+
+// RotateRefreshToken marks token as used and records activity of its session
+func (r *TokenRepository) RotateRefreshToken(tx database.Transaction, token models.RefreshToken, ip string) error {
+	if _, err := tx.Exec(`UPDATE refresh_tokens SET rotated_at = CURRENT_TIMESTAMP WHERE id = $1`, token.ID); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`UPDATE sessions SET last_seen_at = CURRENT_TIMESTAMP, ip = $2 WHERE id = $1`, token.SessionID, ip)
+	return err
+}
+
+// TerminateSession ends session and revokes its refresh tokens
+func (r *TokenRepository) TerminateSession(tx database.Transaction, sessionID uuid.UUID) error {
+	_, err := tx.Exec(`WITH terminated AS (
+		UPDATE sessions SET terminated_at = CURRENT_TIMESTAMP WHERE id = $1 AND terminated_at IS NULL
+	)
+	UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE session_id = $1 AND revoked_at IS NULL`, sessionID)
+	return err
+}
+
+// TerminateUserSession ends running session of user and revokes its refresh tokens
+func (r *TokenRepository) TerminateUserSession(userID uuid.UUID, sessionID uuid.UUID) error {
+	var terminated int
+	err := r.db.Get(&terminated, `WITH terminated AS (
+		UPDATE sessions SET terminated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND user_id = $2 AND terminated_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+		RETURNING id
+	), revoked AS (
+		UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP
+		WHERE session_id IN (SELECT id FROM terminated) AND revoked_at IS NULL
+	)
+	SELECT COUNT(*) FROM terminated`, sessionID, userID)
+	if err != nil {
+		return err
+	}
+	if terminated == 0 {
+		return custom_errors.ErrSessionNotFound
+	}
+	return nil
+}
+
+// TerminateUserSessions ends sessions of user on all devices but the excepted one, uuid.Nil excepts none
+func (r *TokenRepository) TerminateUserSessions(tx database.Transaction, userID uuid.UUID, exceptSessionID uuid.UUID) error {
+	_, err := tx.Exec(`WITH terminated AS (
+		UPDATE sessions SET terminated_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND id <> $2 AND terminated_at IS NULL
+	)
+	UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP
+	WHERE user_id = $1 AND session_id <> $2 AND revoked_at IS NULL`, userID, exceptSessionID)
+	return err
+}
+
+// GetActiveSession returns session of user which isn't terminated. Expired sessions are still active
+// till access tokens issued in them expire.
+func (r *TokenRepository) GetActiveSession(userID uuid.UUID, sessionID uuid.UUID) (models.Session, error) {
+	var session models.Session
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE id = $1 AND user_id = $2 AND terminated_at IS NULL`
+	if err := r.db.Get(&session, query, sessionID, userID); err != nil {
+		if err == sql.ErrNoRows {
+			err = custom_errors.ErrSessionTerminated
+		}
+		return models.Session{}, err
+	}
+	return session, nil
+}
+
+// GetUserSessions returns running sessions of user, the last seen first
+func (r *TokenRepository) GetUserSessions(userID uuid.UUID) ([]models.Session, error) {
+	sessions := []models.Session{}
+	query := `SELECT ` + sessionColumns + ` FROM sessions
+	WHERE user_id = $1 AND terminated_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+	ORDER BY last_seen_at DESC, id`
+	if err := r.db.Select(&sessions, query, userID); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// TouchSession records request of session from ip
+func (r *TokenRepository) TouchSession(sessionID uuid.UUID, ip string) error {
+	_, err := r.db.Exec(`UPDATE sessions SET last_seen_at = CURRENT_TIMESTAMP, ip = $2 WHERE id = $1`, sessionID, ip)
+	return err
+}
+
+// CreatePasswordReset records password reset request, earlier unused requests of user stop working
+func (r *TokenRepository) CreatePasswordReset(tx database.Transaction, reset models.PasswordReset) error {
+	_, err := tx.Exec(`UPDATE password_resets SET used_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND used_at IS NULL`, reset.UserID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`INSERT INTO password_resets (id, user_id, token_hash, created_at, expires_at)
+	VALUES ($1, $2, $3, $4, $5)`, reset.ID, reset.UserID, reset.TokenHash, reset.CreatedAt, reset.ExpiresAt)
+	return err
+}
+
+// UsePasswordReset marks unused and unexpired password reset request of token hash as used and returns its user,
+// so concurrent resets with the same token can't both succeed
+func (r *TokenRepository) UsePasswordReset(tx database.Transaction, tokenHash string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	err := tx.Get(&userID, `UPDATE password_resets SET used_at = CURRENT_TIMESTAMP
+	WHERE token_hash = $1 AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+	RETURNING user_id`, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = custom_errors.ErrInvalidResetToken
+		}
+		return uuid.Nil, err
+	}
+	return userID, nil
+}
+
+func (r *TokenRepository) BeginTransaction() (database.Transaction, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	return &database.CustomTx{Tx: tx}, nil
+}
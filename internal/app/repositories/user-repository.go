@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	custom_errors "edumatch/internal/app/errors"
 	"edumatch/internal/app/models"
+	database "edumatch/pkg/db"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,6 +19,10 @@ type UserRepositoryInterface interface {
 	GetUserByEmail(email string) (models.User, error)
 	GetUserByUsername(username string) (models.User, error)
 	UpdateUser(user models.UpdateUserDto) (models.User, error)
+	GetUserPassword(userID uuid.UUID) (string, error)
+	UpdatePassword(tx database.Transaction, userID uuid.UUID, hashedPassword string) error
+	VerifyEmail(userID uuid.UUID, email string) error
+	MarkVerificationSent(userID uuid.UUID, resendInterval time.Duration) (bool, error)
 	DeleteUser(userID uuid.UUID) error
 }
 type UserRepository struct {
@@ -32,7 +37,7 @@ func NewUserRepository(db *sqlx.DB) UserRepositoryInterface {
 
 func (r *UserRepository) GetUsers() ([]models.User, error) {
 	var users []models.User
-	query := "SELECT id,first_name,last_name,username,COALESCE(email, '') as email,role,created_at,updated_at FROM USERS WHERE deleted_at is null"
+	query := "SELECT id,first_name,last_name,username,COALESCE(email, '') as email,email_verified_at IS NOT NULL as email_verified,role,created_at,updated_at FROM USERS WHERE deleted_at is null"
 	err := r.db.Select(&users, query)
 	if err != nil {
 		return nil, err
@@ -55,7 +60,7 @@ func (r *UserRepository) CreateUser(user models.RegUser) (models.User, error) {
 
 func (r *UserRepository) GetUser(userID uuid.UUID) (models.User, error) {
 	var user models.User
-	query := "SELECT id,first_name,last_name,username,COALESCE(email, '') as email,role,COALESCE(avatar, '') as avatar,created_at,updated_at FROM users WHERE id = $1 AND deleted_at is null"
+	query := "SELECT id,first_name,last_name,username,COALESCE(email, '') as email,email_verified_at IS NOT NULL as email_verified,role,COALESCE(avatar, '') as avatar,created_at,updated_at FROM users WHERE id = $1 AND deleted_at is null"
 	err := r.db.Get(&user, query, userID)
 	if err != nil {
 		//not found
@@ -67,9 +72,10 @@ func (r *UserRepository) GetUser(userID uuid.UUID) (models.User, error) {
 	return user, nil
 }
 
+// GetUserByEmail returns user who verified the email, unverified emails may belong to anyone
 func (r *UserRepository) GetUserByEmail(email string) (models.User, error) {
 	var user models.User
-	query := "SELECT * FROM users WHERE email = $1"
+	query := "SELECT id,first_name,last_name,username,email,true as email_verified,role,COALESCE(avatar, '') as avatar,created_at,updated_at FROM users WHERE email = $1 AND email_verified_at is not null AND deleted_at is null ORDER BY email_verified_at DESC LIMIT 1"
 	err := r.db.Get(&user, query, email)
 	if err != nil {
 		//not found
@@ -100,7 +106,9 @@ func (r *UserRepository) UpdateUser(user models.UpdateUserDto) (models.User, err
 	user.UpdatedAt = time.Now().UTC()
 	query := `
 		UPDATE users
-		SET first_name=:first_name, last_name=:last_name, email=:email, username=:username,avatar=:avatar, updated_at=:updated_at
+		SET first_name=:first_name, last_name=:last_name, email=:email, username=:username,avatar=:avatar, updated_at=:updated_at,
+		email_verified_at=CASE WHEN email IS DISTINCT FROM :email THEN NULL ELSE email_verified_at END,
+		verification_sent_at=CASE WHEN email IS DISTINCT FROM :email THEN NULL ELSE verification_sent_at END
 		WHERE id=:id AND deleted_at is null
 	`
 	// Execute the query
@@ -124,6 +132,68 @@ func (r *UserRepository) UpdateUser(user models.UpdateUserDto) (models.User, err
 	return updatedUser, nil
 }
 
+// GetUserPassword returns password hash of user
+func (r *UserRepository) GetUserPassword(userID uuid.UUID) (string, error) {
+	var password string
+	err := r.db.Get(&password, "SELECT password FROM users WHERE id = $1 AND deleted_at is null", userID)
+	if err != nil {
+		//not found
+		if err == sql.ErrNoRows {
+			err = custom_errors.ErrUserNotFound
+		}
+		return "", err
+	}
+	return password, nil
+}
+
+func (r *UserRepository) UpdatePassword(tx database.Transaction, userID uuid.UUID, hashedPassword string) error {
+	result, err := tx.Exec(`UPDATE users SET password=$2, updated_at=$3 WHERE id=$1 AND deleted_at is null`, userID, hashedPassword, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return custom_errors.ErrUserNotFound
+	}
+	return nil
+}
+
+// VerifyEmail marks email of user verified, link of email user has already changed verifies nothing
+func (r *UserRepository) VerifyEmail(userID uuid.UUID, email string) error {
+	result, err := r.db.Exec(`UPDATE users SET email_verified_at=COALESCE(email_verified_at, CURRENT_TIMESTAMP)
+	WHERE id=$1 AND email=$2 AND deleted_at is null`, userID, email)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return custom_errors.ErrInvalidVerificationToken
+	}
+	return nil
+}
+
+// MarkVerificationSent records sending of verification link unless the previous one was sent within resend interval,
+// the check and the record are one statement so concurrent requests send one link
+func (r *UserRepository) MarkVerificationSent(userID uuid.UUID, resendInterval time.Duration) (bool, error) {
+	result, err := r.db.Exec(`UPDATE users SET verification_sent_at=CURRENT_TIMESTAMP
+	WHERE id=$1 AND deleted_at is null AND (verification_sent_at IS NULL OR verification_sent_at <= CURRENT_TIMESTAMP - make_interval(secs => $2))`,
+		userID, resendInterval.Seconds())
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
 func (r *UserRepository) DeleteUser(userID uuid.UUID) error {
 	_, err := r.db.Exec(`UPDATE users SET deleted_at=$2 WHERE id=$1`, userID, time.Now().UTC())
 	if err != nil {
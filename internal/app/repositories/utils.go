@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"database/sql"
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
+	"edumatch/internal/config"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// queryArgs collects positional arguments of dynamically built queries
+type queryArgs struct {
+	values []interface{}
+}
+
+// add appends value and returns its placeholder
+func (a *queryArgs) add(value interface{}) string {
+	a.values = append(a.values, value)
+	return fmt.Sprintf("$%d", len(a.values))
+}
+
+func joinConditions(conditions []string) string {
+	if len(conditions) == 0 {
+		return "TRUE"
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// escapeLike escapes LIKE wildcards of user input
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+// distanceSQL returns haversine distance in km between location column and given point placeholders
+func distanceSQL(column, latitude, longitude string) string {
+	return fmt.Sprintf(`6371 * ACOS(LEAST(1, SIN(RADIANS(%[2]s)) * SIN(RADIANS(%[1]s[0])) + COS(RADIANS(%[2]s)) * COS(RADIANS(%[1]s[0])) * COS(RADIANS(%[3]s - %[1]s[1]))))`,
+		column, latitude, longitude)
+}
+
+// cursors are opaque for clients: base64 of last sort value and id
+func encodeCursor(sortValue string, id uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(sortValue + "|" + id.String()))
+}
+
+func decodeCursor(cursor string) (string, uuid.UUID, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", uuid.Nil, custom_errors.ErrInvalidCursor
+	}
+	separator := strings.LastIndex(string(decoded), "|")
+	if separator < 0 {
+		return "", uuid.Nil, custom_errors.ErrInvalidCursor
+	}
+	id, err := uuid.Parse(string(decoded[separator+1:]))
+	if err != nil {
+		return "", uuid.Nil, custom_errors.ErrInvalidCursor
+	}
+	return string(decoded[:separator]), id, nil
+}
+
+// pageOffset returns offset of requested page, cursor pagination doesn't use offsets
+func pageOffset(params models.PageQuery) int {
+	if params.Cursor != "" || params.Page < 1 {
+		return 0
+	}
+	return (params.Page - 1) * params.Limit
+}
+
+// keysetCondition builds condition of rows placed after cursor for given sort expression
+func keysetCondition(args *queryArgs, cursor, sortExpr, sortType, order string) (string, error) {
+	sortValue, id, err := decodeCursor(cursor)
+	if err != nil {
+		return "", err
+	}
+	operator := ">"
+	if order == models.OrderDesc {
+		operator = "<"
+	}
+	return fmt.Sprintf("(%s, id) %s (%s::%s, %s)", sortExpr, operator, args.add(sortValue), sortType, args.add(id)), nil
+}
+
+// pointArgs returns coordinates of point as args of POINT, missing point gives NULL location
+func pointArgs(point *models.Point) (latitude, longitude *float64) {
+	if point == nil {
+		return nil, nil
+	}
+	return &point.Latitude, &point.Longitude
+}
+
+// nullTime returns zero time for NULL values
+func nullTime(t sql.NullTime) time.Time {
+	if t.Valid {
+		return t.Time
+	}
+	return time.Time{}
+}
+
+// default weight of prior in bayesian rating, counted in votes
+const defaultRatingPriorCount = 5
+
+// ratingPrior holds weights of bayesian rating: (count*mean + sum of scores) / (count + number of ratings).
+// Without configured mean the average of all ratings of the same target type is used.
+type ratingPrior struct {
+	count float64
+	mean  *float64
+}
+
+// loadRatingPrior reads RATING_PRIOR_COUNT and RATING_PRIOR_MEAN from environment
+func loadRatingPrior() ratingPrior {
+	prior := ratingPrior{count: defaultRatingPriorCount}
+	if count, err := strconv.ParseFloat(config.GetEnv("RATING_PRIOR_COUNT", ""), 64); err == nil && count >= 0 {
+		prior.count = count
+	}
+	if mean, err := strconv.ParseFloat(config.GetEnv("RATING_PRIOR_MEAN", ""), 64); err == nil && mean >= 1 && mean <= 5 {
+		prior.mean = &mean
+	}
+	return prior
+}
+
+// ratingStatsColumns are the columns selected by ratingStatsSQL
+const ratingStatsColumns = `rating, rating_count, weighted_rating, score_1, score_2, score_3, score_4, score_5`
+
+// ratingStatsSQL returns stored rating aggregates of item selected from its table under alias
+func (p ratingPrior) ratingStatsSQL(target models.RatingTarget, alias string) string {
+	mean := fmt.Sprintf(`COALESCE((SELECT SUM(rating_sum)::numeric / NULLIF(SUM(rating_count), 0) FROM %s), 0)`, ratingTargetTables[target])
+	if p.mean != nil {
+		mean = strconv.FormatFloat(*p.mean, 'f', -1, 64)
+	}
+	count := strconv.FormatFloat(p.count, 'f', -1, 64) + "::numeric"
+
+	return fmt.Sprintf(`%[3]s.rating_avg AS rating,
+	%[3]s.rating_count,
+	COALESCE(ROUND((%[1]s * %[2]s + %[3]s.rating_sum) / NULLIF(%[1]s + %[3]s.rating_count, 0), 2), 0) AS weighted_rating,
+	%[3]s.score_1, %[3]s.score_2, %[3]s.score_3, %[3]s.score_4, %[3]s.score_5`, count, mean, alias)
+}
@@ -21,29 +21,87 @@ func ConnectRoutersToHandlers(router *gin.Engine, h dependencies.Handlers) {
 	api.POST("/auth/signup", h.AuthHandler.SignUp)
 	api.POST("/auth/login", h.AuthHandler.Login)
 	api.POST("/auth/refresh", h.AuthHandler.RefreshToken)
+	api.POST("/auth/logout", h.AuthHandler.Logout)
+	api.POST("/auth/logout-all", h.AuthHandler.ProtectedEndpoint(), h.AuthHandler.LogoutAll)
+	api.POST("/auth/password/forgot", h.AuthHandler.ForgotPassword)
+	api.POST("/auth/password/reset", h.AuthHandler.ResetPassword)
+	api.GET("/auth/verify-email", h.UserHandler.VerifyEmail)
 
 	//users
 	api.GET("/users/", h.AuthHandler.ProtectedEndpoint(models.AdminRole), h.UserHandler.GetUsers)
 	api.PATCH("/users/:id", h.AuthHandler.ProtectedEndpoint(), h.UserHandler.UpdateUser)
 	api.GET("/users/:id", h.UserHandler.GetUser)
 	api.DELETE("users/:id", h.AuthHandler.ProtectedEndpoint(), h.UserHandler.DeleteUser)
+	api.GET("/users/me/profile", h.AuthHandler.ProtectedEndpoint(), h.MatchHandler.GetMyProfile)
+	api.PUT("/users/me/profile", h.AuthHandler.ProtectedEndpoint(), h.MatchHandler.SaveMyProfile)
+	api.GET("/users/me/recommendations", h.AuthHandler.ProtectedEndpoint(), h.RecommendationHandler.GetMyRecommendations)
+	api.GET("/users/me/sessions", h.AuthHandler.ProtectedEndpoint(), h.AuthHandler.GetMySessions)
+	api.DELETE("/users/me/sessions/:id", h.AuthHandler.ProtectedEndpoint(), h.AuthHandler.TerminateMySession)
+	api.PUT("/users/me/password", h.AuthHandler.ProtectedEndpoint(), h.AuthHandler.ChangeMyPassword)
+	api.POST("/users/me/email/verification", h.AuthHandler.ProtectedEndpoint(), h.UserHandler.SendVerificationEmail)
 
 	//eduCenters
 	api.GET("/educenters/", h.EduCenterHandler.GetAllEduCenters)
 	api.GET("/educenters/:id", h.EduCenterHandler.GetEduCenter)
-	api.POST("/educenters/", h.AuthHandler.ProtectedEndpoint(), h.EduCenterHandler.CreateEduCenter)
-	api.POST("/educenters/rating", h.AuthHandler.ProtectedEndpoint(), h.EduCenterHandler.GiveRating)
+	api.POST("/educenters/", h.AuthHandler.ProtectedEndpoint(), h.AuthHandler.VerifiedEmailRequired(), h.EduCenterHandler.CreateEduCenter)
+	api.POST("/educenters/rating", h.AuthHandler.ProtectedEndpoint(), h.AuthHandler.VerifiedEmailRequired(), h.EduCenterHandler.GiveRating)
 	api.PATCH("/educenters/:id", h.AuthHandler.ProtectedEndpoint(), h.EduCenterHandler.UpdateEduCenter)
 	api.DELETE("/educenters/:id", h.AuthHandler.ProtectedEndpoint(), h.EduCenterHandler.DeleteEduCenter)
 	api.POST("/educenters/location", h.EduCenterHandler.GetEduCenterByLocation)
+	api.GET("/educenters/map", h.EduCenterHandler.GetEduCenterMap)
+	api.GET("/educenters/:id/rating", h.AuthHandler.ProtectedEndpoint(), h.EduCenterHandler.GetMyRating)
+	api.DELETE("/educenters/:id/rating", h.AuthHandler.ProtectedEndpoint(), h.EduCenterHandler.DeleteRating)
+	api.GET("/educenters/:id/reviews", h.ReviewHandler.GetEduCenterReviews)
+	api.PUT("/educenters/:id/review", h.AuthHandler.ProtectedEndpoint(), h.ReviewHandler.SaveEduCenterReview)
+	api.GET("/educenters/:id/courses", h.CourseHandler.GetEduCenterCourses)
+	api.POST("/educenters/:id/courses", h.AuthHandler.ProtectedEndpoint(), h.CourseHandler.CreateEduCenterCourse)
+	api.GET("/educenters/:id/ratings", h.RatingResponseHandler.GetEduCenterRatings)
+	api.GET("/educenters/:id/calendar.ics", h.CourseHandler.GetEduCenterCalendar)
 
 	//courses
 	api.GET("/courses/", h.CourseHandler.GetAllCourses)
 	api.GET("/courses/:id", h.CourseHandler.GetCourse)
 	api.POST("/courses/", h.AuthHandler.ProtectedEndpoint(), h.CourseHandler.CreateCourse)
-	api.POST("/courses/rating", h.AuthHandler.ProtectedEndpoint(), h.CourseHandler.GiveRating)
+	api.POST("/courses/rating", h.AuthHandler.ProtectedEndpoint(), h.AuthHandler.VerifiedEmailRequired(), h.CourseHandler.GiveRating)
 	api.PATCH("/courses/", h.AuthHandler.ProtectedEndpoint(), h.CourseHandler.UpdateCourse)
 	api.DELETE("/courses/:id", h.AuthHandler.ProtectedEndpoint(), h.CourseHandler.DeleteCourse)
+	api.GET("/courses/:id/rating", h.AuthHandler.ProtectedEndpoint(), h.CourseHandler.GetMyRating)
+	api.DELETE("/courses/:id/rating", h.AuthHandler.ProtectedEndpoint(), h.CourseHandler.DeleteRating)
+	api.GET("/courses/:id/reviews", h.ReviewHandler.GetCourseReviews)
+	api.PUT("/courses/:id/review", h.AuthHandler.ProtectedEndpoint(), h.ReviewHandler.SaveCourseReview)
+	api.GET("/courses/:id/similar", h.RecommendationHandler.GetSimilarCourses)
+	api.GET("/courses/:id/schedules", h.ScheduleHandler.GetCourseSchedules)
+	api.POST("/courses/:id/schedules", h.AuthHandler.ProtectedEndpoint(), h.ScheduleHandler.CreateSchedule)
+	api.PUT("/courses/:id/schedules/:schedule_id", h.AuthHandler.ProtectedEndpoint(), h.ScheduleHandler.UpdateSchedule)
+	api.DELETE("/courses/:id/schedules/:schedule_id", h.AuthHandler.ProtectedEndpoint(), h.ScheduleHandler.DeleteSchedule)
+	api.GET("/courses/:id/discounts", h.CourseHandler.GetCourseDiscounts)
+	api.POST("/courses/:id/discounts", h.AuthHandler.ProtectedEndpoint(), h.CourseHandler.CreateDiscount)
+	api.DELETE("/courses/:id/discounts/:discount_id", h.AuthHandler.ProtectedEndpoint(), h.CourseHandler.DeleteDiscount)
+	api.GET("/courses/:id/calendar.ics", h.CourseHandler.GetCourseCalendar)
+
+	//reviews
+	api.POST("/reviews/:id/helpful", h.AuthHandler.ProtectedEndpoint(), h.ReviewHandler.VoteHelpful)
+	api.DELETE("/reviews/:id/helpful", h.AuthHandler.ProtectedEndpoint(), h.ReviewHandler.DeleteHelpfulVote)
+
+	//rating responses
+	api.GET("/ratings/unanswered", h.AuthHandler.ProtectedEndpoint(), h.RatingResponseHandler.GetUnansweredRatings)
+	api.PUT("/ratings/:id/response", h.AuthHandler.ProtectedEndpoint(), h.RatingResponseHandler.SaveResponse)
+	api.DELETE("/ratings/:id/response", h.AuthHandler.ProtectedEndpoint(), h.RatingResponseHandler.DeleteResponse)
+
+	//search
+	api.GET("/search", h.SearchHandler.Search)
+	api.GET("/suggest", h.SearchHandler.Suggest)
+
+	//match
+	api.GET("/match", h.AuthHandler.ProtectedEndpoint(), h.MatchHandler.Match)
+
+	//geocoding
+	api.GET("/geocode/reverse", h.GeocodingHandler.ReverseGeocode)
+
+	//moderation
+	api.POST("/reports", h.AuthHandler.ProtectedEndpoint(), h.ModerationHandler.ReportItem)
+	api.GET("/moderation/reports", h.AuthHandler.ProtectedEndpoint(models.AdminRole), h.ModerationHandler.GetReports)
+	api.POST("/moderation/reports/:id/decision", h.AuthHandler.ProtectedEndpoint(models.AdminRole), h.ModerationHandler.DecideReport)
 
 	url := ginSwagger.URL("swagger/doc.json")
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, url))
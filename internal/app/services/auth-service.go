@@ -3,83 +3,388 @@ package services
 import (
 	custom_errors "edumatch/internal/app/errors"
 	"edumatch/internal/app/models"
+	"edumatch/internal/app/repositories"
+	"edumatch/internal/app/validators"
+	database "edumatch/pkg/db"
+	"edumatch/pkg/mailer"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type AuthServiceInterface interface {
-	RegisterUser(user models.RegUser) (models.Tokens, error)
-	Login(models.LoggingUser) (models.Tokens, error)
+	RegisterUser(user models.RegUser, client models.Client) (models.Tokens, error)
+	Login(loggingUser models.LoggingUser, client models.Client) (models.Tokens, error)
+	RefreshTokens(refreshToken string, client models.Client) (models.Tokens, error)
+	Logout(refreshToken string) error
+	LogoutAll(userID uuid.UUID) error
 	UserStillExists(userID uuid.UUID) bool
+	CheckSession(claims models.AccessClaims, client models.Client) error
+	GetSessions(userID uuid.UUID, currentSessionID uuid.UUID) (models.Sessions, error)
+	TerminateSession(userID uuid.UUID, sessionID uuid.UUID) error
+	ChangePassword(userID uuid.UUID, sessionID uuid.UUID, dto models.ChangePasswordDto) error
+	ForgotPassword(dto models.ForgotPasswordDto) error
+	ResetPassword(dto models.ResetPasswordDto) error
+	CheckEmailVerified(userID uuid.UUID) error
 }
 
+// last seen time of session is updated at most this often
+const sessionTouchInterval = time.Minute
+
 type AuthService struct {
-	userService UserServiceInterface
+	userService     UserServiceInterface
+	userRepository  repositories.UserRepositoryInterface
+	tokenRepository repositories.TokenRepositoryInterface
+	validator       validators.AuthValidatorInterface
+	mailer          mailer.Mailer
+	// page of client where user sets new password, token is added to its query
+	passwordResetURL string
+	// whether users without verified email are kept from creating edu centers and rating
+	requireVerifiedEmail bool
 }
 
-func NewAuthService(userService UserServiceInterface) AuthServiceInterface {
+func NewAuthService(userService UserServiceInterface, userRepository repositories.UserRepositoryInterface, tokenRepository repositories.TokenRepositoryInterface, authValidator validators.AuthValidatorInterface, mailer mailer.Mailer, passwordResetURL string, requireVerifiedEmail bool) AuthServiceInterface {
 	return &AuthService{
-		userService: userService,
+		userService:          userService,
+		userRepository:       userRepository,
+		tokenRepository:      tokenRepository,
+		validator:            authValidator,
+		mailer:               mailer,
+		passwordResetURL:     passwordResetURL,
+		requireVerifiedEmail: requireVerifiedEmail,
 	}
 }
 
-func (s *AuthService) RegisterUser(user models.RegUser) (models.Tokens, error) {
+func (s *AuthService) RegisterUser(user models.RegUser, client models.Client) (models.Tokens, error) {
 	// create a new user
 	createdUser, err := s.userService.CreateUser(user)
 	if err != nil {
 		return models.Tokens{}, err
 	}
-	// retrieve the user ID
-	userID := createdUser.ID
-	role := createdUser.Role
 
-	// Generate a new JWT token
-	var accessToken string
-	accessToken, err = GenerateToken(userID, role, false)
+	return s.startSession(createdUser.ID, createdUser.Role, client)
+}
+
+func (s *AuthService) Login(loggingUser models.LoggingUser, client models.Client) (models.Tokens, error) {
+	user, err := s.userService.GetUserByUsername(loggingUser.UserName)
 	if err != nil {
 		return models.Tokens{}, err
 	}
+	//check password
+	if !CheckPassword(user.Password, loggingUser.Password) {
+		return models.Tokens{}, custom_errors.ErrWrongPassword
+	}
 
-	// Generate a new refresh token
-	var refreshToken string
-	refreshToken, err = GenerateToken(userID, role, true)
+	return s.startSession(user.ID, user.Role, client)
+}
+
+// RefreshTokens exchanges refresh token for new access and refresh tokens of the same session, the old one
+// can't be used again. Presenting rotated token again means it was copied, so the session is terminated.
+func (s *AuthService) RefreshTokens(refreshToken string, client models.Client) (models.Tokens, error) {
+	claims, err := ValidateRefreshToken(refreshToken)
+	if err != nil {
+		return models.Tokens{}, err
+	}
+	//role may have changed since login
+	user, err := s.userService.GetUser(claims.UserID)
+	if err != nil {
+		return models.Tokens{}, custom_errors.ErrUserNoLongerExist
+	}
+
+	tx, err := s.tokenRepository.BeginTransaction()
 	if err != nil {
 		return models.Tokens{}, err
 	}
+	token, err := s.tokenRepository.LockRefreshToken(tx, claims.TokenID)
+	if err != nil {
+		tx.Rollback()
+		return models.Tokens{}, err
+	}
+	if token.UserID != claims.UserID || token.RevokedAt != nil || !token.ExpiresAt.After(time.Now()) {
+		tx.Rollback()
+		return models.Tokens{}, custom_errors.ErrInvalidToken
+	}
+	if token.RotatedAt != nil {
+		//termination must be kept, so transaction is committed
+		if err := s.tokenRepository.TerminateSession(tx, token.SessionID); err != nil {
+			tx.Rollback()
+			return models.Tokens{}, err
+		}
+		if err := tx.Commit(); err != nil {
+			return models.Tokens{}, err
+		}
+		return models.Tokens{}, custom_errors.ErrRefreshTokenReused
+	}
 
-	tokens := models.Tokens{AccessToken: accessToken, RefreshToken: refreshToken}
+	if err := s.tokenRepository.RotateRefreshToken(tx, token, client.IP); err != nil {
+		tx.Rollback()
+		return models.Tokens{}, err
+	}
+	tokens, err := s.createTokens(tx, user.ID, user.Role, token.SessionID)
+	if err != nil {
+		tx.Rollback()
+		return models.Tokens{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return models.Tokens{}, err
+	}
 	return tokens, nil
 }
 
-func (s *AuthService) Login(loggingUser models.LoggingUser) (models.Tokens, error) {
-	user, err := s.userService.GetUserByUsername(loggingUser.UserName)
+// Logout terminates session of refresh token, so the device it was issued to has to log in again
+func (s *AuthService) Logout(refreshToken string) error {
+	claims, err := ValidateRefreshToken(refreshToken)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.tokenRepository.BeginTransaction()
+	if err != nil {
+		return err
+	}
+	token, err := s.tokenRepository.LockRefreshToken(tx, claims.TokenID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := s.tokenRepository.TerminateSession(tx, token.SessionID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// LogoutAll terminates sessions of user on all devices
+func (s *AuthService) LogoutAll(userID uuid.UUID) error {
+	tx, err := s.tokenRepository.BeginTransaction()
+	if err != nil {
+		return err
+	}
+	if err := s.tokenRepository.TerminateUserSessions(tx, userID, uuid.Nil); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *AuthService) UserStillExists(userID uuid.UUID) bool {
+	_, err := s.userService.GetUser(userID)
+	return err == nil
+}
+
+// CheckSession rejects access tokens of terminated sessions and records activity of running ones
+func (s *AuthService) CheckSession(claims models.AccessClaims, client models.Client) error {
+	session, err := s.tokenRepository.GetActiveSession(claims.UserID, claims.SessionID)
+	if err != nil {
+		return err
+	}
+	if time.Since(session.LastSeenAt) < sessionTouchInterval && session.IP == client.IP {
+		return nil
+	}
+	return s.tokenRepository.TouchSession(session.ID, client.IP)
+}
+
+func (s *AuthService) GetSessions(userID uuid.UUID, currentSessionID uuid.UUID) (models.Sessions, error) {
+	sessions, err := s.tokenRepository.GetUserSessions(userID)
+	if err != nil {
+		return models.Sessions{}, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+	return models.Sessions{Sessions: sessions}, nil
+}
+
+// TerminateSession terminates session of user, access tokens of it are rejected right away
+func (s *AuthService) TerminateSession(userID uuid.UUID, sessionID uuid.UUID) error {
+	return s.tokenRepository.TerminateUserSession(userID, sessionID)
+}
+
+// ChangePassword sets new password of user who knows the old one, other sessions of user are terminated
+func (s *AuthService) ChangePassword(userID uuid.UUID, sessionID uuid.UUID, dto models.ChangePasswordDto) error {
+	if err := s.validator.ValidateChangePassword(&dto); err != nil {
+		return err
+	}
+	password, err := s.userRepository.GetUserPassword(userID)
+	if err != nil {
+		return err
+	}
+	if !CheckPassword(password, dto.OldPassword) {
+		return custom_errors.ErrWrongPassword
+	}
+	hashedPassword, err := HashPassword(dto.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.tokenRepository.BeginTransaction()
+	if err != nil {
+		return err
+	}
+	if err := s.savePassword(tx, userID, hashedPassword, sessionID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// ForgotPassword mails link to reset password to user who verified the email. Unknown email isn't reported
+// and mail is sent in background, so the endpoint can't be used to find out who is registered.
+func (s *AuthService) ForgotPassword(dto models.ForgotPasswordDto) error {
+	if err := s.validator.ValidateForgotPassword(&dto); err != nil {
+		return err
+	}
+	user, err := s.userService.GetUserByEmail(dto.Email)
+	if err != nil {
+		if err == custom_errors.ErrUserNotFound {
+			return nil
+		}
+		return err
+	}
+
+	token, tokenHash, err := GenerateSecretToken()
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	reset := models.PasswordReset{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		CreatedAt: now,
+		ExpiresAt: now.Add(PasswordResetLifetime()),
+	}
+	tx, err := s.tokenRepository.BeginTransaction()
+	if err != nil {
+		return err
+	}
+	if err := s.tokenRepository.CreatePasswordReset(tx, reset); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	link, err := LinkWithToken(s.passwordResetURL, token)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your EduMatch password",
+		Body: fmt.Sprintf("Hello %s,\n\nSomeone asked to reset password of your EduMatch account %s. "+
+			"Open the link below to set a new one, it works once within %d minutes:\n\n%s\n\n"+
+			"If it wasn't you, ignore this email, your password stays the same.\n",
+			user.FirstName, user.Username, int(PasswordResetLifetime().Minutes()), link),
+	})
+}
+
+// ResetPassword sets new password of user by mailed token, the token is used up and all sessions of user are terminated
+func (s *AuthService) ResetPassword(dto models.ResetPasswordDto) error {
+	if err := s.validator.ValidateResetPassword(&dto); err != nil {
+		return err
+	}
+	hashedPassword, err := HashPassword(dto.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.tokenRepository.BeginTransaction()
+	if err != nil {
+		return err
+	}
+	userID, err := s.tokenRepository.UsePasswordReset(tx, HashSecretToken(dto.Token))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := s.savePassword(tx, userID, hashedPassword, uuid.Nil); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// CheckEmailVerified rejects user without verified email when verified email is required
+func (s *AuthService) CheckEmailVerified(userID uuid.UUID) error {
+	if !s.requireVerifiedEmail {
+		return nil
+	}
+	user, err := s.userService.GetUser(userID)
+	if err != nil {
+		return err
+	}
+	if !user.EmailVerified {
+		return custom_errors.ErrEmailNotVerified
+	}
+	return nil
+}
+
+// savePassword saves new password hash of user and terminates sessions of user but the kept one
+func (s *AuthService) savePassword(tx database.Transaction, userID uuid.UUID, hashedPassword string, keptSessionID uuid.UUID) error {
+	if err := s.userRepository.UpdatePassword(tx, userID, hashedPassword); err != nil {
+		return err
+	}
+	//tokens issued with the old password stop working
+	return s.tokenRepository.TerminateUserSessions(tx, userID, keptSessionID)
+}
+
+// startSession records new session of client and issues its first tokens
+func (s *AuthService) startSession(userID uuid.UUID, role models.Role, client models.Client) (models.Tokens, error) {
+	tx, err := s.tokenRepository.BeginTransaction()
 	if err != nil {
 		return models.Tokens{}, err
 	}
-	//check password
-	if !CheckPassword(user.Password, loggingUser.Password) {
-		return models.Tokens{}, custom_errors.ErrWrongPassword
+	session := models.Session{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Device:    client.Device,
+		IP:        client.IP,
+		CreatedAt: time.Now().UTC(),
+	}
+	session.ExpiresAt = session.CreatedAt.Add(RefreshTokenLifetime())
+	if err := s.tokenRepository.CreateSession(tx, session); err != nil {
+		tx.Rollback()
+		return models.Tokens{}, err
 	}
+	tokens, err := s.createTokens(tx, userID, role, session.ID)
+	if err != nil {
+		tx.Rollback()
+		return models.Tokens{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return models.Tokens{}, err
+	}
+	return tokens, nil
+}
 
+// createTokens records new refresh token of session and signs it together with access token
+func (s *AuthService) createTokens(tx database.Transaction, userID uuid.UUID, role models.Role, sessionID uuid.UUID) (models.Tokens, error) {
 	// Generate a new JWT token
-	var token string
-	token, err = GenerateToken(user.ID, user.Role, false)
+	accessToken, err := GenerateToken(userID, role, sessionID)
 	if err != nil {
 		return models.Tokens{}, err
 	}
 
+	now := time.Now().UTC()
+	record := models.RefreshToken{
+		ID:        uuid.New(),
+		SessionID: sessionID,
+		UserID:    userID,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(RefreshTokenLifetime()),
+	}
+	if err := s.tokenRepository.CreateRefreshToken(tx, record); err != nil {
+		return models.Tokens{}, err
+	}
+
 	// Generate a new refresh token
-	var refreshToken string
-	refreshToken, err = GenerateToken(user.ID, user.Role, true)
+	refreshToken, err := GenerateRefreshToken(userID, record.ID, record.ExpiresAt)
 	if err != nil {
 		return models.Tokens{}, err
 	}
 
-	tokens := models.Tokens{AccessToken: token, RefreshToken: refreshToken}
-	return tokens, nil
-}
-
-func (s *AuthService) UserStillExists(userID uuid.UUID) bool {
-	_, err := s.userService.GetUser(userID)
-	return err == nil
+	return models.Tokens{AccessToken: accessToken, RefreshToken: refreshToken}, nil
 }
@@ -3,30 +3,65 @@ package services
 import (
 	"edumatch/internal/app/models"
 	"edumatch/internal/app/repositories"
+	"edumatch/internal/app/validators"
+	"edumatch/pkg/ical"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type CourseServiceInterface interface {
-	CreateCourse(course models.CreateCourseDto) (models.Course, error)
-	UpdateCourse(newCourse models.UpdateCourseDto) (models.Course, error)
+	CreateCourse(course models.CreateCourseDto, requester models.Requester) (models.Course, error)
+	UpdateCourse(newCourse models.UpdateCourseDto, requester models.Requester) (models.Course, error)
 	GetCourse(id uuid.UUID) (models.Course, error)
-	GetAllCourses() (models.AllCourses, error)
-	DeleteCourse(id uuid.UUID) error
-	GiveRating(rating models.CourseRating) error
+	GetAllCourses(query models.CourseQuery) (models.AllCourses, error)
+	DeleteCourse(id uuid.UUID, requester models.Requester) error
+	GiveRating(rating models.CourseRating) (models.CourseRating, error)
+	GetUserRating(courseID uuid.UUID, userID uuid.UUID) (models.CourseRating, error)
+	DeleteRating(courseID uuid.UUID, userID uuid.UUID) error
+	GetEduCenterCourses(eduCenterID uuid.UUID, query models.CourseQuery) (models.AllCourses, error)
+	CreateEduCenterCourse(eduCenterID uuid.UUID, course models.CreateCourseDto, requester models.Requester) (models.Course, error)
+	GetCourseSummary(eduCenterID uuid.UUID) (models.CourseSummary, error)
+	GetCourseDiscounts(courseID uuid.UUID) (models.CourseDiscounts, error)
+	CreateDiscount(discount models.CourseDiscountDto, requester models.Requester) (models.CourseDiscount, error)
+	DeleteDiscount(courseID uuid.UUID, discountID uuid.UUID, requester models.Requester) error
+	GetCourseCalendar(courseID uuid.UUID) ([]byte, error)
+	GetEduCenterCalendar(eduCenterID uuid.UUID) ([]byte, error)
 }
 
+// number of best rated courses shown in center summary
+const topRatedCoursesLimit = 3
+
+// product identifier of generated calendars
+const calendarProdID = "-//EduMatch//Course Timetable//EN"
+
 type CourseService struct {
 	courseRepository repositories.CourseRepositoryInterface
+	validator        validators.CourseValidatorInterface
+	// time zone lessons are held in
+	calendarLocation *time.Location
 }
 
-func NewCourseService(courseRepasitory repositories.CourseRepositoryInterface) CourseServiceInterface {
+func NewCourseService(courseRepasitory repositories.CourseRepositoryInterface, courseValidator validators.CourseValidatorInterface, calendarLocation *time.Location) CourseServiceInterface {
 	return &CourseService{
 		courseRepository: courseRepasitory,
+		validator:        courseValidator,
+		calendarLocation: calendarLocation,
 	}
 }
 
-func (s *CourseService) CreateCourse(course models.CreateCourseDto) (models.Course, error) {
+func (s *CourseService) CreateCourse(course models.CreateCourseDto, requester models.Requester) (models.Course, error) {
+	//validate price
+	if err := s.validatePrice(course.Price); err != nil {
+		return models.Course{}, err
+	}
+	//only owner of center can add courses to it
+	if err := s.checkEduCenterOwnership(course.EduCenterID, requester); err != nil {
+		return models.Course{}, err
+	}
+
 	newCourse, err := s.courseRepository.CreateCourse(course)
 	if err != nil {
 		return models.Course{}, err
@@ -34,7 +69,19 @@ func (s *CourseService) CreateCourse(course models.CreateCourseDto) (models.Cour
 	return newCourse, nil
 }
 
-func (s *CourseService) UpdateCourse(newCourse models.UpdateCourseDto) (models.Course, error) {
+func (s *CourseService) UpdateCourse(newCourse models.UpdateCourseDto, requester models.Requester) (models.Course, error) {
+	//validate price
+	if err := s.validatePrice(newCourse.Price); err != nil {
+		return models.Course{}, err
+	}
+	if err := s.checkCourseOwnership(newCourse.ID, requester); err != nil {
+		return models.Course{}, err
+	}
+	//course can be moved only to center of the same owner
+	if err := s.checkEduCenterOwnership(newCourse.EduCenterID, requester); err != nil {
+		return models.Course{}, err
+	}
+
 	course, err := s.courseRepository.UpdateCourse(newCourse)
 	if err != nil {
 		return models.Course{}, err
@@ -50,25 +97,270 @@ func (s *CourseService) GetCourse(id uuid.UUID) (models.Course, error) {
 	return course, nil
 }
 
-func (s *CourseService) GetAllCourses() (models.AllCourses, error) {
-	courses, err := s.courseRepository.GetAllCourses()
+func (s *CourseService) GetAllCourses(query models.CourseQuery) (models.AllCourses, error) {
+	query.Currency = strings.ToUpper(query.Currency)
+	//validate query
+	if err := s.validator.ValidateCourseQuery(&query); err != nil {
+		return models.AllCourses{}, err
+	}
+	if query.SortBy == "" {
+		query.SortBy = models.CourseSortRating
+	}
+	NormalizePageQuery(&query.PageQuery, models.OrderDesc)
+
+	courses, err := s.courseRepository.GetAllCourses(query)
 	if err != nil {
 		return models.AllCourses{}, err
 	}
+	courses.Pagination = ResponsePagination(query.PageQuery, courses.NextCursor)
 	return courses, nil
 }
 
-func (s *CourseService) DeleteCourse(id uuid.UUID) error {
+func (s *CourseService) DeleteCourse(id uuid.UUID, requester models.Requester) error {
+	if err := s.checkCourseOwnership(id, requester); err != nil {
+		return err
+	}
 	if err := s.courseRepository.DeleteCourse(id); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s *CourseService) GiveRating(rating models.CourseRating) error {
-	if err := s.courseRepository.GiveRating(rating); err != nil {
+func (s *CourseService) GiveRating(rating models.CourseRating) (models.CourseRating, error) {
+	//validate rating
+	if err := s.validator.ValidateCourseRating(&rating); err != nil {
+		return models.CourseRating{}, err
+	}
+
+	//rating and aggregates of rated item are changed together
+	tx, err := s.courseRepository.BeginTransaction()
+	if err != nil {
+		return models.CourseRating{}, err
+	}
+
+	savedRating, err := s.courseRepository.GiveRating(tx, rating)
+	if err != nil {
+		tx.Rollback()
+		return models.CourseRating{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.CourseRating{}, err
+	}
+
+	return savedRating, nil
+}
+
+func (s *CourseService) GetUserRating(courseID uuid.UUID, userID uuid.UUID) (models.CourseRating, error) {
+	rating, err := s.courseRepository.GetUserRating(courseID, userID)
+	if err != nil {
+		return models.CourseRating{}, err
+	}
+
+	return rating, nil
+}
+
+func (s *CourseService) DeleteRating(courseID uuid.UUID, userID uuid.UUID) error {
+	tx, err := s.courseRepository.BeginTransaction()
+	if err != nil {
 		return err
 	}
 
-	return nil
+	if err := s.courseRepository.DeleteRating(tx, courseID, userID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *CourseService) GetEduCenterCourses(eduCenterID uuid.UUID, query models.CourseQuery) (models.AllCourses, error) {
+	query.Currency = strings.ToUpper(query.Currency)
+	//validate query
+	if err := s.validator.ValidateCourseQuery(&query); err != nil {
+		return models.AllCourses{}, err
+	}
+	if query.SortBy == "" {
+		query.SortBy = models.CourseSortRating
+	}
+	NormalizePageQuery(&query.PageQuery, models.OrderDesc)
+
+	courses, err := s.courseRepository.GetEduCenterCourses(eduCenterID, query)
+	if err != nil {
+		return models.AllCourses{}, err
+	}
+	courses.Pagination = ResponsePagination(query.PageQuery, courses.NextCursor)
+
+	return courses, nil
+}
+
+func (s *CourseService) CreateEduCenterCourse(eduCenterID uuid.UUID, course models.CreateCourseDto, requester models.Requester) (models.Course, error) {
+	course.EduCenterID = eduCenterID
+	return s.CreateCourse(course, requester)
+}
+
+func (s *CourseService) GetCourseSummary(eduCenterID uuid.UUID) (models.CourseSummary, error) {
+	summary, err := s.courseRepository.GetCourseSummary(eduCenterID, topRatedCoursesLimit)
+	if err != nil {
+		return models.CourseSummary{}, err
+	}
+	return summary, nil
+}
+
+func (s *CourseService) GetCourseDiscounts(courseID uuid.UUID) (models.CourseDiscounts, error) {
+	discounts, err := s.courseRepository.GetCourseDiscounts(courseID)
+	if err != nil {
+		return models.CourseDiscounts{}, err
+	}
+	return models.CourseDiscounts{Discounts: discounts}, nil
+}
+
+func (s *CourseService) CreateDiscount(discount models.CourseDiscountDto, requester models.Requester) (models.CourseDiscount, error) {
+	//validate discount
+	if err := s.validator.ValidateCourseDiscount(&discount); err != nil {
+		return models.CourseDiscount{}, err
+	}
+	if err := s.checkCourseOwnership(discount.CourseID, requester); err != nil {
+		return models.CourseDiscount{}, err
+	}
+
+	return s.courseRepository.CreateDiscount(discount)
+}
+
+func (s *CourseService) DeleteDiscount(courseID uuid.UUID, discountID uuid.UUID, requester models.Requester) error {
+	if err := s.checkCourseOwnership(courseID, requester); err != nil {
+		return err
+	}
+	return s.courseRepository.DeleteDiscount(courseID, discountID)
+}
+
+// GetCourseCalendar returns iCalendar feed of groups of course
+func (s *CourseService) GetCourseCalendar(courseID uuid.UUID) ([]byte, error) {
+	timetable, err := s.courseRepository.GetCourseTimetable(courseID)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildCalendar(timetable)
+}
+
+// GetEduCenterCalendar returns iCalendar feed of groups of all courses of edu center
+func (s *CourseService) GetEduCenterCalendar(eduCenterID uuid.UUID) ([]byte, error) {
+	timetable, err := s.courseRepository.GetEduCenterTimetable(eduCenterID)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildCalendar(timetable)
+}
+
+// buildCalendar makes weekly recurring event of each slot starting from its first day in period of group.
+// UID of event is made of group and position of slot in it, so editing day or time of slot moves its event.
+func (s *CourseService) buildCalendar(timetable models.Timetable) ([]byte, error) {
+	calendar := ical.Calendar{
+		ProdID:   calendarProdID,
+		Name:     timetable.Name,
+		Location: s.calendarLocation,
+		Events:   make([]ical.Event, 0, len(timetable.Slots)),
+	}
+
+	for _, slot := range timetable.Slots {
+		startDate, err := time.ParseInLocation("2006-01-02", slot.StartDate, s.calendarLocation)
+		if err != nil {
+			return nil, err
+		}
+		//first lesson is on the first day of week of slot since start of group
+		day := time.Weekday(slot.DayOfWeek)
+		firstDate := startDate.AddDate(0, 0, (int(day)-int(startDate.Weekday())+7)%7)
+
+		var until time.Time
+		if slot.EndDate != "" {
+			endDate, err := time.ParseInLocation("2006-01-02", slot.EndDate, s.calendarLocation)
+			if err != nil {
+				return nil, err
+			}
+			if firstDate.After(endDate) {
+				//group ends before its first lesson on this day
+				continue
+			}
+			until = endDate.AddDate(0, 0, 1).Add(-time.Second)
+		}
+
+		start, err := atClock(firstDate, slot.StartTime)
+		if err != nil {
+			return nil, err
+		}
+		end, err := atClock(firstDate, slot.EndTime)
+		if err != nil {
+			return nil, err
+		}
+
+		calendar.Events = append(calendar.Events, ical.Event{
+			UID:         fmt.Sprintf("%s-%d@edumatch", slot.ScheduleID, slot.Position),
+			Stamp:       slot.UpdatedAt,
+			Start:       start,
+			End:         end,
+			RRule:       ical.WeeklyRule(day, until),
+			Summary:     eventSummary(slot),
+			Location:    joinNonEmpty(", ", slot.Room, slot.EduCenterName, slot.Address),
+			Description: joinNonEmpty("\n", prefixNonEmpty("Teacher: ", slot.Teacher), prefixNonEmpty("Language: ", slot.Language)),
+		})
+	}
+
+	return calendar.Encode(), nil
+}
+
+func (s *CourseService) validatePrice(price *models.CoursePriceDto) error {
+	if price == nil {
+		return nil
+	}
+	price.Currency = strings.ToUpper(price.Currency)
+	return s.validator.ValidateCoursePrice(price)
+}
+
+func (s *CourseService) checkCourseOwnership(courseID uuid.UUID, requester models.Requester) error {
+	ownerID, err := s.courseRepository.GetCourseOwner(courseID)
+	if err != nil {
+		return err
+	}
+	return CheckOwnership(requester, ownerID)
+}
+
+func (s *CourseService) checkEduCenterOwnership(eduCenterID uuid.UUID, requester models.Requester) error {
+	ownerID, err := s.courseRepository.GetEduCenterOwner(eduCenterID)
+	if err != nil {
+		return err
+	}
+	return CheckOwnership(requester, ownerID)
+}
+
+// atClock returns date at given "15:04" time
+func atClock(date time.Time, clock string) (time.Time, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), 0, 0, date.Location()), nil
+}
+
+func eventSummary(slot models.TimetableSlot) string {
+	if slot.ScheduleName == "" {
+		return slot.CourseName
+	}
+	return fmt.Sprintf("%s (%s)", slot.CourseName, slot.ScheduleName)
+}
+
+func joinNonEmpty(separator string, values ...string) string {
+	var nonEmpty []string
+	for _, value := range values {
+		if value != "" {
+			nonEmpty = append(nonEmpty, value)
+		}
+	}
+	return strings.Join(nonEmpty, separator)
+}
+
+func prefixNonEmpty(prefix, value string) string {
+	if value == "" {
+		return ""
+	}
+	return prefix + value
 }
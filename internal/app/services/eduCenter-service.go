@@ -1,31 +1,51 @@
 package services
 
 import (
+	custom_errors "edumatch/internal/app/errors"
 	"edumatch/internal/app/models"
 	"edumatch/internal/app/repositories"
 	"edumatch/internal/app/validators"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 )
 
 type EduCenterServiceInterface interface {
 	CreateEduCenter(eduCenter models.CreateEduCenterDto) (models.EduCenter, error)
-	GetAllEduCenters() (models.AllEduCenters, error)
-	GetEduCenter(eduCenterID uuid.UUID) (models.EduCenter, error)
-	UpdateEduCenter(eduCenter models.UpdateEduCenterDto) (models.EduCenter, error)
-	DeleteEduCenter(eduCenterID uuid.UUID) error
-	GiveRating(rating models.EduCenterRating) error
+	GetAllEduCenters(query models.EduCenterQuery) (models.AllEduCenters, error)
+	GetEduCenter(eduCenterID uuid.UUID, includeCourses bool) (models.EduCenter, error)
+	UpdateEduCenter(eduCenter models.UpdateEduCenterDto, requester models.Requester) (models.EduCenter, error)
+	DeleteEduCenter(eduCenterID uuid.UUID, requester models.Requester) error
+	GiveRating(rating models.EduCenterRating) (models.EduCenterRating, error)
+	GetUserRating(eduCenterID uuid.UUID, userID uuid.UUID) (models.EduCenterRating, error)
+	DeleteRating(eduCenterID uuid.UUID, userID uuid.UUID) error
 	GetEduCenterByLocation(location models.NearEduCenterDto) (models.AllNearEduCenters, error)
+	GetEduCenterMap(query models.EduCenterMapQuery) (models.EduCenterMap, error)
 }
+
+const (
+	// map tile of 256px is split into cells of 64px, centers in the same cell are clustered
+	mapCellsPerTile = 4
+	mapCellsLimit   = 1000
+)
+
 type EduCenterService struct {
 	eduCenterRepository repositories.EduCenterRepositoryInterface
 	validator           validators.EduCenterValidatorInterface
+	courseService       CourseServiceInterface
+	geocoder            GeocoderInterface
 }
 
-func NewEduCenterService(eduCenterRepository repositories.EduCenterRepositoryInterface, eduCenterValidator validators.EduCenterValidatorInterface) EduCenterServiceInterface {
+func NewEduCenterService(eduCenterRepository repositories.EduCenterRepositoryInterface, eduCenterValidator validators.EduCenterValidatorInterface, courseService CourseServiceInterface, geocoder GeocoderInterface) EduCenterServiceInterface {
 	return &EduCenterService{
 		eduCenterRepository: eduCenterRepository,
 		validator:           eduCenterValidator,
+		courseService:       courseService,
+		geocoder:            geocoder,
 	}
 }
 
@@ -34,6 +54,9 @@ func (s *EduCenterService) CreateEduCenter(eduCenter models.CreateEduCenterDto)
 	if err := s.validator.ValidateEduCenterCreate(&eduCenter); err != nil {
 		return models.EduCenter{}, err
 	}
+	if err := s.fillAddressOrLocation(&eduCenter.Address, &eduCenter.Location); err != nil {
+		return models.EduCenter{}, err
+	}
 	var imageName string
 	var err error
 	if eduCenter.CoverImage != nil {
@@ -74,26 +97,55 @@ func (s *EduCenterService) CreateEduCenter(eduCenter models.CreateEduCenterDto)
 	return newEduCenter, err
 }
 
-func (s *EduCenterService) GetAllEduCenters() (models.AllEduCenters, error) {
-	eduCenters, err := s.eduCenterRepository.GetAllEduCenters()
+func (s *EduCenterService) GetAllEduCenters(query models.EduCenterQuery) (models.AllEduCenters, error) {
+	//validate query
+	if err := s.validator.ValidateEduCenterQuery(&query); err != nil {
+		return models.AllEduCenters{}, err
+	}
+	//best rated and newest centers first, others in natural order
+	if query.SortBy == "" {
+		query.SortBy = models.EduCenterSortRating
+	}
+	defaultOrder := models.OrderAsc
+	if query.SortBy == models.EduCenterSortRating || query.SortBy == models.EduCenterSortCreatedAt {
+		defaultOrder = models.OrderDesc
+	}
+	NormalizePageQuery(&query.PageQuery, defaultOrder)
+
+	eduCenters, err := s.eduCenterRepository.GetAllEduCenters(query)
 	if err != nil {
 		return models.AllEduCenters{}, err
 	}
+	eduCenters.Pagination = ResponsePagination(query.PageQuery, eduCenters.NextCursor)
 
 	return eduCenters, nil
 }
 
-func (s *EduCenterService) GetEduCenter(eduCenterID uuid.UUID) (models.EduCenter, error) {
+func (s *EduCenterService) GetEduCenter(eduCenterID uuid.UUID, includeCourses bool) (models.EduCenter, error) {
 	eduCenter, err := s.eduCenterRepository.GetEduCenter(eduCenterID)
 	if err != nil {
 		return models.EduCenter{}, err
 	}
 
+	if includeCourses {
+		summary, err := s.courseService.GetCourseSummary(eduCenterID)
+		if err != nil {
+			return models.EduCenter{}, err
+		}
+		eduCenter.Courses = &summary
+	}
+
 	return eduCenter, nil
 }
 
 // todo later we should make them in goroutines
-func (s *EduCenterService) UpdateEduCenter(eduCenter models.UpdateEduCenterDto) (models.EduCenter, error) {
+func (s *EduCenterService) UpdateEduCenter(eduCenter models.UpdateEduCenterDto, requester models.Requester) (models.EduCenter, error) {
+	if err := s.checkOwnership(eduCenter.ID, requester); err != nil {
+		return models.EduCenter{}, err
+	}
+	if err := s.fillAddressOrLocation(&eduCenter.Address, &eduCenter.Location); err != nil {
+		return models.EduCenter{}, err
+	}
 	//todo
 	//should be validated
 	eduCenter.CoverImageUrl = eduCenter.OldCoverImage
@@ -140,7 +192,10 @@ func (s *EduCenterService) UpdateEduCenter(eduCenter models.UpdateEduCenterDto)
 	return updatedEduCenter, nil
 }
 
-func (s *EduCenterService) DeleteEduCenter(eduCenterID uuid.UUID) error {
+func (s *EduCenterService) DeleteEduCenter(eduCenterID uuid.UUID, requester models.Requester) error {
+	if err := s.checkOwnership(eduCenterID, requester); err != nil {
+		return err
+	}
 	if err := s.eduCenterRepository.DeleteEduCenter(eduCenterID); err != nil {
 		return err
 	}
@@ -148,20 +203,163 @@ func (s *EduCenterService) DeleteEduCenter(eduCenterID uuid.UUID) error {
 	return nil
 }
 
-func (s *EduCenterService) GiveRating(rating models.EduCenterRating) error {
-	if err := s.eduCenterRepository.GiveRating(rating); err != nil {
+func (s *EduCenterService) GiveRating(rating models.EduCenterRating) (models.EduCenterRating, error) {
+	//validate rating
+	if err := s.validator.ValidateEduCenterRating(&rating); err != nil {
+		return models.EduCenterRating{}, err
+	}
+
+	//rating and aggregates of rated item are changed together
+	tx, err := s.eduCenterRepository.BeginTransaction()
+	if err != nil {
+		return models.EduCenterRating{}, err
+	}
+
+	savedRating, err := s.eduCenterRepository.GiveRating(tx, rating)
+	if err != nil {
+		tx.Rollback()
+		return models.EduCenterRating{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.EduCenterRating{}, err
+	}
+
+	return savedRating, nil
+}
+
+func (s *EduCenterService) GetUserRating(eduCenterID uuid.UUID, userID uuid.UUID) (models.EduCenterRating, error) {
+	rating, err := s.eduCenterRepository.GetUserRating(eduCenterID, userID)
+	if err != nil {
+		return models.EduCenterRating{}, err
+	}
+
+	return rating, nil
+}
+
+func (s *EduCenterService) DeleteRating(eduCenterID uuid.UUID, userID uuid.UUID) error {
+	tx, err := s.eduCenterRepository.BeginTransaction()
+	if err != nil {
 		return err
 	}
 
-	return nil
+	if err := s.eduCenterRepository.DeleteRating(tx, eduCenterID, userID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (s *EduCenterService) GetEduCenterByLocation(location models.NearEduCenterDto) (models.AllNearEduCenters, error) {
+	//validate location
+	if err := s.validator.ValidateNearEduCenter(&location); err != nil {
+		return models.AllNearEduCenters{}, err
+	}
+	if location.Limit == 0 {
+		location.Limit = models.DefaultPageLimit
+	}
+
 	eduCenters, err := s.eduCenterRepository.GetEduCenterByLocation(location)
 	if err != nil {
-		return models.AllNearEduCenters{}, nil
+		return models.AllNearEduCenters{}, err
+	}
+	return eduCenters, nil
+}
+
+func (s *EduCenterService) GetEduCenterMap(query models.EduCenterMapQuery) (models.EduCenterMap, error) {
+	//validate query
+	if err := s.validator.ValidateEduCenterMapQuery(&query); err != nil {
+		return models.EduCenterMap{}, err
+	}
+	bbox, err := parseBoundingBox(query.Bbox)
+	if err != nil {
+		return models.EduCenterMap{}, err
 	}
-	return models.AllNearEduCenters{
-		EduCenters: eduCenters,
-	}, nil
+
+	cellSize := 360 / math.Pow(2, float64(*query.Zoom)) / mapCellsPerTile
+	clusters, err := s.eduCenterRepository.GetEduCenterMap(query, bbox, cellSize, mapCellsLimit)
+	if err != nil {
+		return models.EduCenterMap{}, err
+	}
+
+	eduCenterMap := models.EduCenterMap{
+		Zoom:       *query.Zoom,
+		Clusters:   []models.MapCluster{},
+		EduCenters: []models.MapEduCenter{},
+	}
+	for _, cluster := range clusters {
+		if cluster.Count == 1 {
+			eduCenterMap.EduCenters = append(eduCenterMap.EduCenters, cluster.TopRated)
+			continue
+		}
+		eduCenterMap.Clusters = append(eduCenterMap.Clusters, cluster)
+	}
+
+	return eduCenterMap, nil
+}
+
+// parseBoundingBox parses "min_longitude,min_latitude,max_longitude,max_latitude"
+func parseBoundingBox(value string) (models.BoundingBox, error) {
+	invalid := fmt.Errorf("%s : %s", custom_errors.ErrValidation, "bbox must be min_longitude,min_latitude,max_longitude,max_latitude")
+
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return models.BoundingBox{}, invalid
+	}
+	var coordinates [4]float64
+	for i, part := range parts {
+		coordinate, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return models.BoundingBox{}, invalid
+		}
+		coordinates[i] = coordinate
+	}
+
+	bbox := models.BoundingBox{
+		MinLongitude: coordinates[0],
+		MinLatitude:  coordinates[1],
+		MaxLongitude: coordinates[2],
+		MaxLatitude:  coordinates[3],
+	}
+	if bbox.MinLatitude < -90 || bbox.MaxLatitude > 90 || bbox.MinLongitude < -180 || bbox.MaxLongitude > 180 ||
+		bbox.MinLatitude > bbox.MaxLatitude || bbox.MinLongitude > bbox.MaxLongitude {
+		return models.BoundingBox{}, invalid
+	}
+
+	return bbox, nil
+}
+
+func (s *EduCenterService) checkOwnership(eduCenterID uuid.UUID, requester models.Requester) error {
+	ownerID, err := s.eduCenterRepository.GetEduCenterOwner(eduCenterID)
+	if err != nil {
+		return err
+	}
+	return CheckOwnership(requester, ownerID)
+}
+
+// fillAddressOrLocation geocodes address when location is not given and the other way around.
+// Places unknown to geocoder are left as given.
+func (s *EduCenterService) fillAddressOrLocation(address *string, location **models.Point) error {
+	switch {
+	case *location == nil && strings.TrimSpace(*address) != "":
+		point, err := s.geocoder.Geocode(*address)
+		if err != nil {
+			if errors.Is(err, custom_errors.ErrPlaceNotFound) {
+				return nil
+			}
+			return err
+		}
+		*location = &point
+	case strings.TrimSpace(*address) == "" && *location != nil:
+		geocoded, err := s.geocoder.ReverseGeocode(**location)
+		if err != nil {
+			if errors.Is(err, custom_errors.ErrPlaceNotFound) {
+				return nil
+			}
+			return err
+		}
+		*address = geocoded.Address
+	}
+	return nil
 }
@@ -0,0 +1,292 @@
+package services
+
+import (
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GeocoderInterface converts free text addresses to points and back and completes names of places.
+// Implementations return custom_errors.ErrPlaceNotFound when they know nothing about the place.
+type GeocoderInterface interface {
+	Geocode(address string) (models.Point, error)
+	ReverseGeocode(location models.Point) (models.GeocodedAddress, error)
+	SuggestPlaces(prefix, kind string, limit int) ([]models.Suggestion, error)
+}
+
+// the more precise place the more it weighs in address match
+var placeRanks = map[string]int{
+	models.PlaceCity:     1,
+	models.PlaceDistrict: 2,
+	models.PlaceStreet:   4,
+}
+
+// reverse geocoding looks for the nearest place of each kind not farther than radius in km
+var reverseGeocodeRadiuses = []struct {
+	kind   string
+	radius float64
+}{
+	{models.PlaceStreet, 1.5},
+	{models.PlaceDistrict, 8},
+	{models.PlaceCity, 40},
+}
+
+const earthRadiusKm = 6371
+
+type gazetteerEntry struct {
+	place    models.Place
+	names    []string
+	district string
+	city     string
+}
+
+// GazetteerGeocoder geocodes against centroids of cities, districts and streets loaded from file, so it needs no network
+type GazetteerGeocoder struct {
+	entries []gazetteerEntry
+}
+
+// NewGazetteerGeocoder loads gazetteer csv with header kind,names,district,city,latitude,longitude.
+// Names are separated by "|", the first one is used in addresses. Empty path gives geocoder without places.
+func NewGazetteerGeocoder(path string) (GeocoderInterface, error) {
+	geocoder := &GazetteerGeocoder{}
+	if path == "" {
+		return geocoder, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	places, err := readGazetteer(file)
+	if err != nil {
+		return nil, fmt.Errorf("gazetteer %s: %v", path, err)
+	}
+	for _, place := range places {
+		entry := gazetteerEntry{
+			place:    place,
+			district: normalizePlaceName(place.District),
+			city:     normalizePlaceName(place.City),
+		}
+		for _, name := range place.Names {
+			entry.names = append(entry.names, normalizePlaceName(name))
+		}
+		geocoder.entries = append(geocoder.entries, entry)
+	}
+	return geocoder, nil
+}
+
+func readGazetteer(r io.Reader) ([]models.Place, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 6
+	reader.Comment = '#'
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var places []models.Place
+	//first record is the header
+	for i := 1; i < len(records); i++ {
+		record := records[i]
+		kind := strings.TrimSpace(record[0])
+		if _, ok := placeRanks[kind]; !ok {
+			return nil, fmt.Errorf("record %d: unknown kind %q", i, kind)
+		}
+		var names []string
+		for _, name := range strings.Split(record[1], "|") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("record %d: name is required", i)
+		}
+		latitude, err := strconv.ParseFloat(strings.TrimSpace(record[4]), 64)
+		if err != nil || latitude < -90 || latitude > 90 {
+			return nil, fmt.Errorf("record %d: invalid latitude %q", i, record[4])
+		}
+		longitude, err := strconv.ParseFloat(strings.TrimSpace(record[5]), 64)
+		if err != nil || longitude < -180 || longitude > 180 {
+			return nil, fmt.Errorf("record %d: invalid longitude %q", i, record[5])
+		}
+		places = append(places, models.Place{
+			Kind:     kind,
+			Names:    names,
+			District: strings.TrimSpace(record[2]),
+			City:     strings.TrimSpace(record[3]),
+			Location: models.Point{Latitude: latitude, Longitude: longitude},
+		})
+	}
+	return places, nil
+}
+
+// Geocode finds the most precise place named in address. Cities and districts named in address
+// narrow down the places, so the same street name in different cities is not confused.
+func (g *GazetteerGeocoder) Geocode(address string) (models.Point, error) {
+	text := " " + normalizePlaceName(address) + " "
+
+	cities := map[string]bool{}
+	districts := map[string]bool{}
+	for _, entry := range g.entries {
+		if !entry.mentionedIn(text) {
+			continue
+		}
+		switch entry.place.Kind {
+		case models.PlaceCity:
+			cities[entry.names[0]] = true
+		case models.PlaceDistrict:
+			districts[entry.names[0]] = true
+		}
+	}
+
+	best, bestScore := -1, 0
+	for i, entry := range g.entries {
+		if !entry.mentionedIn(text) {
+			continue
+		}
+		city, district := entry.city, entry.district
+		switch entry.place.Kind {
+		case models.PlaceCity:
+			city = entry.names[0]
+		case models.PlaceDistrict:
+			district = entry.names[0]
+		}
+		if len(cities) > 0 && city != "" && !cities[city] {
+			continue
+		}
+		if len(districts) > 0 && district != "" && !districts[district] {
+			continue
+		}
+
+		score := placeRanks[entry.place.Kind]
+		if entry.place.Kind != models.PlaceDistrict && districts[district] {
+			score += placeRanks[models.PlaceDistrict]
+		}
+		if entry.place.Kind != models.PlaceCity && cities[city] {
+			score += placeRanks[models.PlaceCity]
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 {
+		return models.Point{}, custom_errors.ErrPlaceNotFound
+	}
+	return g.entries[best].place.Location, nil
+}
+
+// ReverseGeocode describes point by the nearest street, district or city around it
+func (g *GazetteerGeocoder) ReverseGeocode(location models.Point) (models.GeocodedAddress, error) {
+	for _, search := range reverseGeocodeRadiuses {
+		nearest, nearestDistance := -1, search.radius
+		for i, entry := range g.entries {
+			if entry.place.Kind != search.kind {
+				continue
+			}
+			if distance := distanceKm(location, entry.place.Location); distance <= nearestDistance {
+				nearest, nearestDistance = i, distance
+			}
+		}
+		if nearest >= 0 {
+			return geocodedAddress(g.entries[nearest].place, nearestDistance), nil
+		}
+	}
+	return models.GeocodedAddress{}, custom_errors.ErrPlaceNotFound
+}
+
+// SuggestPlaces completes prefix with names of places of kind, places whose name starts with prefix come first
+func (g *GazetteerGeocoder) SuggestPlaces(prefix, kind string, limit int) ([]models.Suggestion, error) {
+	prefix = normalizePlaceName(prefix)
+	suggestions := []models.Suggestion{}
+	if prefix == "" {
+		return suggestions, nil
+	}
+	for _, entry := range g.entries {
+		if entry.place.Kind != kind {
+			continue
+		}
+		match := -1
+		for _, name := range entry.names {
+			switch {
+			case strings.HasPrefix(name, prefix):
+				match = 0
+			case match < 0 && strings.Contains(name, " "+prefix):
+				match = 1
+			}
+		}
+		if match >= 0 {
+			suggestions = append(suggestions, models.Suggestion{Type: kind, Text: entry.place.Names[0], Match: match})
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Match != suggestions[j].Match {
+			return suggestions[i].Match < suggestions[j].Match
+		}
+		return suggestions[i].Text < suggestions[j].Text
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
+
+func (e gazetteerEntry) mentionedIn(text string) bool {
+	for _, name := range e.names {
+		if name != "" && strings.Contains(text, " "+name+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+func geocodedAddress(place models.Place, distance float64) models.GeocodedAddress {
+	address := models.GeocodedAddress{
+		District: place.District,
+		City:     place.City,
+		Location: place.Location,
+		Distance: distance,
+	}
+	switch place.Kind {
+	case models.PlaceStreet:
+		address.Street = place.Names[0]
+	case models.PlaceDistrict:
+		address.District = place.Names[0]
+	case models.PlaceCity:
+		address.City = place.Names[0]
+	}
+
+	var parts []string
+	for _, part := range []string{address.Street, address.District, address.City} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	address.Address = strings.Join(parts, ", ")
+	return address
+}
+
+// normalizePlaceName lowercases name and replaces punctuation with single spaces
+func normalizePlaceName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// distanceKm is the haversine distance between points
+func distanceKm(a, b models.Point) float64 {
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (b.Longitude - a.Longitude) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
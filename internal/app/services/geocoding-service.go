@@ -0,0 +1,31 @@
+package services
+
+import (
+	"edumatch/internal/app/models"
+	"edumatch/internal/app/validators"
+)
+
+type GeocodingServiceInterface interface {
+	ReverseGeocode(query models.ReverseGeocodeQuery) (models.GeocodedAddress, error)
+}
+
+type GeocodingService struct {
+	geocoder  GeocoderInterface
+	validator validators.GeocodingValidatorInterface
+}
+
+func NewGeocodingService(geocoder GeocoderInterface, geocodingValidator validators.GeocodingValidatorInterface) GeocodingServiceInterface {
+	return &GeocodingService{
+		geocoder:  geocoder,
+		validator: geocodingValidator,
+	}
+}
+
+func (s *GeocodingService) ReverseGeocode(query models.ReverseGeocodeQuery) (models.GeocodedAddress, error) {
+	//validate query
+	if err := s.validator.ValidateReverseGeocodeQuery(&query); err != nil {
+		return models.GeocodedAddress{}, err
+	}
+
+	return s.geocoder.ReverseGeocode(models.Point{Latitude: *query.Latitude, Longitude: *query.Longitude})
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"edumatch/internal/app/models"
+	"edumatch/internal/app/repositories"
+	"edumatch/internal/app/validators"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type MatchServiceInterface interface {
+	GetStudentProfile(userID uuid.UUID) (models.StudentProfile, error)
+	SaveStudentProfile(profile models.StudentProfileDto) (models.StudentProfile, error)
+	Match(userID uuid.UUID, query models.MatchQuery) (models.Matches, error)
+}
+
+type MatchService struct {
+	matchRepository repositories.MatchRepositoryInterface
+	validator       validators.MatchValidatorInterface
+}
+
+func NewMatchService(matchRepository repositories.MatchRepositoryInterface, matchValidator validators.MatchValidatorInterface) MatchServiceInterface {
+	return &MatchService{
+		matchRepository: matchRepository,
+		validator:       matchValidator,
+	}
+}
+
+func (s *MatchService) GetStudentProfile(userID uuid.UUID) (models.StudentProfile, error) {
+	return s.matchRepository.GetStudentProfile(userID)
+}
+
+func (s *MatchService) SaveStudentProfile(profile models.StudentProfileDto) (models.StudentProfile, error) {
+	for i := range profile.Subjects {
+		profile.Subjects[i] = strings.TrimSpace(profile.Subjects[i])
+	}
+	profile.Currency = strings.ToUpper(profile.Currency)
+	//validate profile
+	if err := s.validator.ValidateStudentProfile(&profile); err != nil {
+		return models.StudentProfile{}, err
+	}
+
+	return s.matchRepository.SaveStudentProfile(profile)
+}
+
+func (s *MatchService) Match(userID uuid.UUID, query models.MatchQuery) (models.Matches, error) {
+	//validate query
+	if err := s.validator.ValidateMatchQuery(&query); err != nil {
+		return models.Matches{}, err
+	}
+	NormalizePageQuery(&query.PageQuery, models.OrderDesc)
+
+	profile, err := s.matchRepository.GetStudentProfile(userID)
+	if err != nil {
+		return models.Matches{}, err
+	}
+	matches, err := s.matchRepository.Match(profile, query)
+	if err != nil {
+		return models.Matches{}, err
+	}
+	matches.Pagination = ResponsePagination(query.PageQuery, matches.NextCursor)
+
+	return matches, nil
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
+	"edumatch/internal/app/repositories"
+	"edumatch/internal/app/validators"
+
+	"github.com/google/uuid"
+)
+
+type ModerationServiceInterface interface {
+	ReportItem(report models.CreateReportDto) (models.Report, error)
+	GetReports(query models.ReportQuery) (models.AllReports, error)
+	DecideReport(reportID uuid.UUID, decision models.ModerationDecisionDto) (models.Report, error)
+}
+
+type ModerationService struct {
+	moderationRepository repositories.ModerationRepositoryInterface
+	validator            validators.ModerationValidatorInterface
+}
+
+func NewModerationService(moderationRepository repositories.ModerationRepositoryInterface, moderationValidator validators.ModerationValidatorInterface) ModerationServiceInterface {
+	return &ModerationService{
+		moderationRepository: moderationRepository,
+		validator:            moderationValidator,
+	}
+}
+
+func (s *ModerationService) ReportItem(report models.CreateReportDto) (models.Report, error) {
+	//validate report
+	if err := s.validator.ValidateReport(&report); err != nil {
+		return models.Report{}, err
+	}
+
+	createdReport, err := s.moderationRepository.CreateReport(report)
+	if err != nil {
+		return models.Report{}, err
+	}
+
+	return createdReport, nil
+}
+
+func (s *ModerationService) GetReports(query models.ReportQuery) (models.AllReports, error) {
+	//validate query
+	if err := s.validator.ValidateReportQuery(&query); err != nil {
+		return models.AllReports{}, err
+	}
+	//queue is handled from oldest reports
+	NormalizePageQuery(&query.PageQuery, models.OrderAsc)
+
+	reports, err := s.moderationRepository.GetReports(query)
+	if err != nil {
+		return models.AllReports{}, err
+	}
+	reports.Pagination = ResponsePagination(query.PageQuery, reports.NextCursor)
+
+	return reports, nil
+}
+
+func (s *ModerationService) DecideReport(reportID uuid.UUID, decision models.ModerationDecisionDto) (models.Report, error) {
+	//validate decision
+	if err := s.validator.ValidateDecision(&decision); err != nil {
+		return models.Report{}, err
+	}
+
+	report, err := s.moderationRepository.GetReport(reportID)
+	if err != nil {
+		return models.Report{}, err
+	}
+	if report.Status != models.ReportPending {
+		return models.Report{}, custom_errors.ErrReportResolved
+	}
+
+	//item and reports are changed together
+	tx, err := s.moderationRepository.BeginTransaction()
+	if err != nil {
+		return models.Report{}, err
+	}
+
+	if err := s.moderationRepository.ApplyModeration(tx, report.TargetType, report.TargetID, decision.Action); err != nil {
+		tx.Rollback()
+		return models.Report{}, err
+	}
+
+	resolvedReport, err := s.moderationRepository.ResolveReports(tx, report, decision)
+	if err != nil {
+		tx.Rollback()
+		return models.Report{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Report{}, err
+	}
+
+	return resolvedReport, nil
+}
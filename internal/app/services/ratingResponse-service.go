@@ -0,0 +1,105 @@
+package services
+
+import (
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
+	"edumatch/internal/app/repositories"
+	"edumatch/internal/app/validators"
+
+	"github.com/google/uuid"
+)
+
+type RatingResponseServiceInterface interface {
+	SaveResponse(ratingID uuid.UUID, response models.RatingResponseDto) (models.RatingResponse, error)
+	DeleteResponse(ratingID uuid.UUID, ownerID uuid.UUID) error
+	GetEduCenterRatings(eduCenterID uuid.UUID, query models.PageQuery) (models.AllRatingsWithResponses, error)
+	GetUnansweredRatings(ownerID uuid.UUID, query models.UnansweredRatingsQuery) (models.AllRatingsWithResponses, error)
+}
+
+type RatingResponseService struct {
+	ratingResponseRepository repositories.RatingResponseRepositoryInterface
+	validator                validators.RatingResponseValidatorInterface
+}
+
+func NewRatingResponseService(ratingResponseRepository repositories.RatingResponseRepositoryInterface, ratingResponseValidator validators.RatingResponseValidatorInterface) RatingResponseServiceInterface {
+	return &RatingResponseService{
+		ratingResponseRepository: ratingResponseRepository,
+		validator:                ratingResponseValidator,
+	}
+}
+
+func (s *RatingResponseService) SaveResponse(ratingID uuid.UUID, response models.RatingResponseDto) (models.RatingResponse, error) {
+	//validate response
+	if err := s.validator.ValidateResponse(&response); err != nil {
+		return models.RatingResponse{}, err
+	}
+	if err := s.checkCenterOwner(ratingID, response.OwnerID); err != nil {
+		return models.RatingResponse{}, err
+	}
+
+	savedResponse, err := s.ratingResponseRepository.SaveResponse(ratingID, response)
+	if err != nil {
+		return models.RatingResponse{}, err
+	}
+
+	return savedResponse, nil
+}
+
+func (s *RatingResponseService) DeleteResponse(ratingID uuid.UUID, ownerID uuid.UUID) error {
+	if err := s.checkCenterOwner(ratingID, ownerID); err != nil {
+		return err
+	}
+
+	if err := s.ratingResponseRepository.DeleteResponse(ratingID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *RatingResponseService) GetEduCenterRatings(eduCenterID uuid.UUID, query models.PageQuery) (models.AllRatingsWithResponses, error) {
+	//validate query
+	if err := s.validator.ValidatePageQuery(&query); err != nil {
+		return models.AllRatingsWithResponses{}, err
+	}
+	NormalizePageQuery(&query, models.OrderDesc)
+
+	ratings, err := s.ratingResponseRepository.GetEduCenterRatings(eduCenterID, query)
+	if err != nil {
+		return models.AllRatingsWithResponses{}, err
+	}
+	ratings.Pagination = ResponsePagination(query, ratings.NextCursor)
+
+	return ratings, nil
+}
+
+func (s *RatingResponseService) GetUnansweredRatings(ownerID uuid.UUID, query models.UnansweredRatingsQuery) (models.AllRatingsWithResponses, error) {
+	//validate query
+	if err := s.validator.ValidateUnansweredQuery(&query); err != nil {
+		return models.AllRatingsWithResponses{}, err
+	}
+	if query.MaxScore == 0 {
+		query.MaxScore = models.DefaultLowRatingScore
+	}
+	NormalizePageQuery(&query.PageQuery, models.OrderDesc)
+
+	ratings, err := s.ratingResponseRepository.GetUnansweredRatings(ownerID, query)
+	if err != nil {
+		return models.AllRatingsWithResponses{}, err
+	}
+	ratings.Pagination = ResponsePagination(query.PageQuery, ratings.NextCursor)
+
+	return ratings, nil
+}
+
+// checkCenterOwner allows only owner of rated center to respond, admins moderate through reports
+func (s *RatingResponseService) checkCenterOwner(ratingID uuid.UUID, userID uuid.UUID) error {
+	ownerID, err := s.ratingResponseRepository.GetRatingCenterOwner(ratingID)
+	if err != nil {
+		return err
+	}
+	if ownerID != userID {
+		return custom_errors.ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
+	"edumatch/internal/app/repositories"
+	"edumatch/internal/app/validators"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+type RecommendationServiceInterface interface {
+	ComputeSimilarities() (models.ComputedSimilarities, error)
+	GetSimilarCourses(courseID uuid.UUID, query models.SimilarQuery) (models.Recommendations, error)
+	GetUserRecommendations(userID uuid.UUID, query models.RecommendationQuery) (models.Recommendations, error)
+}
+
+// radius in km popular items are recommended from when student profile has no max distance
+const defaultPopularRadius = 10
+
+type RecommendationService struct {
+	recommendationRepository repositories.RecommendationRepositoryInterface
+	validator                validators.RecommendationValidatorInterface
+	matchService             MatchServiceInterface
+}
+
+func NewRecommendationService(recommendationRepository repositories.RecommendationRepositoryInterface, recommendationValidator validators.RecommendationValidatorInterface, matchService MatchServiceInterface) RecommendationServiceInterface {
+	return &RecommendationService{
+		recommendationRepository: recommendationRepository,
+		validator:                recommendationValidator,
+		matchService:             matchService,
+	}
+}
+
+func (s *RecommendationService) ComputeSimilarities() (models.ComputedSimilarities, error) {
+	return s.recommendationRepository.ComputeSimilarities()
+}
+
+func (s *RecommendationService) GetSimilarCourses(courseID uuid.UUID, query models.SimilarQuery) (models.Recommendations, error) {
+	//validate query
+	if err := s.validator.ValidateSimilarQuery(&query); err != nil {
+		return models.Recommendations{}, err
+	}
+	if query.Limit <= 0 {
+		query.Limit = models.DefaultRecommendationLimit
+	}
+
+	courses, err := s.recommendationRepository.GetSimilarCourses(courseID, query.Limit)
+	if err != nil {
+		return models.Recommendations{}, err
+	}
+	return models.Recommendations{Recommendations: courses}, nil
+}
+
+// GetUserRecommendations recommends items similar to the ones user rated. Users without rating history
+// get popular items around given point or home of their student profile.
+func (s *RecommendationService) GetUserRecommendations(userID uuid.UUID, query models.RecommendationQuery) (models.Recommendations, error) {
+	//validate query
+	if err := s.validator.ValidateRecommendationQuery(&query); err != nil {
+		return models.Recommendations{}, err
+	}
+	if query.Limit <= 0 {
+		query.Limit = models.DefaultRecommendationLimit
+	}
+
+	recommendations, err := s.recommendationRepository.GetUserRecommendations(userID, query.Limit)
+	if err != nil {
+		return models.Recommendations{}, err
+	}
+	if len(recommendations) > 0 {
+		return models.Recommendations{Recommendations: recommendations}, nil
+	}
+
+	var location *models.Point
+	radius := float64(defaultPopularRadius)
+	if query.Latitude != nil && query.Longitude != nil {
+		location = &models.Point{Latitude: *query.Latitude, Longitude: *query.Longitude}
+	}
+	profile, err := s.matchService.GetStudentProfile(userID)
+	if err != nil && !errors.Is(err, custom_errors.ErrStudentProfileNotFound) {
+		return models.Recommendations{}, err
+	}
+	if location == nil {
+		location = profile.HomeLocation
+	}
+	if profile.MaxDistance != nil {
+		radius = *profile.MaxDistance
+	}
+
+	recommendations, err = s.recommendationRepository.GetPopularRecommendations(location, radius, query.Limit)
+	if err != nil {
+		return models.Recommendations{}, err
+	}
+	return models.Recommendations{Recommendations: recommendations}, nil
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
+	"edumatch/internal/app/repositories"
+	"edumatch/internal/app/validators"
+
+	"github.com/google/uuid"
+)
+
+type ReviewServiceInterface interface {
+	SaveReview(target models.RatingTarget, targetID uuid.UUID, userID uuid.UUID, review models.ReviewDto) (models.Review, error)
+	GetReviews(target models.RatingTarget, targetID uuid.UUID, query models.ReviewQuery) (models.AllReviews, error)
+	VoteHelpful(reviewID uuid.UUID, userID uuid.UUID) error
+	DeleteHelpfulVote(reviewID uuid.UUID, userID uuid.UUID) error
+}
+
+type ReviewService struct {
+	reviewRepository repositories.ReviewRepositoryInterface
+	validator        validators.ReviewValidatorInterface
+}
+
+func NewReviewService(reviewRepository repositories.ReviewRepositoryInterface, reviewValidator validators.ReviewValidatorInterface) ReviewServiceInterface {
+	return &ReviewService{
+		reviewRepository: reviewRepository,
+		validator:        reviewValidator,
+	}
+}
+
+func (s *ReviewService) SaveReview(target models.RatingTarget, targetID uuid.UUID, userID uuid.UUID, review models.ReviewDto) (models.Review, error) {
+	//validate review
+	if err := s.validator.ValidateReview(&review); err != nil {
+		return models.Review{}, err
+	}
+
+	savedReview, err := s.reviewRepository.SaveReview(target, targetID, userID, review)
+	if err != nil {
+		return models.Review{}, err
+	}
+
+	return savedReview, nil
+}
+
+func (s *ReviewService) GetReviews(target models.RatingTarget, targetID uuid.UUID, query models.ReviewQuery) (models.AllReviews, error) {
+	//validate query
+	if err := s.validator.ValidateReviewQuery(&query); err != nil {
+		return models.AllReviews{}, err
+	}
+	if query.SortBy == "" {
+		query.SortBy = models.ReviewSortNewest
+	}
+	NormalizePageQuery(&query.PageQuery, models.OrderDesc)
+
+	reviews, err := s.reviewRepository.GetReviews(target, targetID, query)
+	if err != nil {
+		return models.AllReviews{}, err
+	}
+	reviews.Pagination = ResponsePagination(query.PageQuery, reviews.NextCursor)
+
+	return reviews, nil
+}
+
+func (s *ReviewService) VoteHelpful(reviewID uuid.UUID, userID uuid.UUID) error {
+	authorID, err := s.reviewRepository.GetReviewAuthor(reviewID)
+	if err != nil {
+		return err
+	}
+	//authors can't promote their own reviews
+	if authorID == userID {
+		return custom_errors.ErrVoteOwnReview
+	}
+
+	if err := s.reviewRepository.VoteHelpful(reviewID, userID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *ReviewService) DeleteHelpfulVote(reviewID uuid.UUID, userID uuid.UUID) error {
+	if err := s.reviewRepository.DeleteHelpfulVote(reviewID, userID); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
+	"edumatch/internal/app/repositories"
+	"edumatch/internal/app/validators"
+	"fmt"
+	"time"
+
+	database "edumatch/pkg/db"
+	"github.com/google/uuid"
+)
+
+type ScheduleServiceInterface interface {
+	GetCourseSchedules(courseID uuid.UUID) (models.CourseSchedules, error)
+	CreateSchedule(schedule models.CourseScheduleDto, requester models.Requester) (models.CourseSchedule, error)
+	UpdateSchedule(schedule models.CourseScheduleDto, requester models.Requester) (models.CourseSchedule, error)
+	DeleteSchedule(courseID uuid.UUID, scheduleID uuid.UUID, requester models.Requester) error
+}
+
+type ScheduleService struct {
+	scheduleRepository repositories.ScheduleRepositoryInterface
+	validator          validators.ScheduleValidatorInterface
+}
+
+func NewScheduleService(scheduleRepository repositories.ScheduleRepositoryInterface, scheduleValidator validators.ScheduleValidatorInterface) ScheduleServiceInterface {
+	return &ScheduleService{
+		scheduleRepository: scheduleRepository,
+		validator:          scheduleValidator,
+	}
+}
+
+func (s *ScheduleService) GetCourseSchedules(courseID uuid.UUID) (models.CourseSchedules, error) {
+	schedules, err := s.scheduleRepository.GetCourseSchedules(courseID)
+	if err != nil {
+		return models.CourseSchedules{}, err
+	}
+	return models.CourseSchedules{Schedules: schedules}, nil
+}
+
+func (s *ScheduleService) CreateSchedule(schedule models.CourseScheduleDto, requester models.Requester) (models.CourseSchedule, error) {
+	//validate schedule
+	if err := s.validator.ValidateSchedule(&schedule); err != nil {
+		return models.CourseSchedule{}, err
+	}
+	schedule.ID = uuid.Nil
+
+	err := s.saveSchedule(schedule, requester, func(tx database.Transaction) error {
+		var err error
+		schedule.ID, err = s.scheduleRepository.CreateSchedule(tx, schedule)
+		return err
+	})
+	if err != nil {
+		return models.CourseSchedule{}, err
+	}
+	return s.scheduleRepository.GetCourseSchedule(schedule.CourseID, schedule.ID)
+}
+
+func (s *ScheduleService) UpdateSchedule(schedule models.CourseScheduleDto, requester models.Requester) (models.CourseSchedule, error) {
+	//validate schedule
+	if err := s.validator.ValidateSchedule(&schedule); err != nil {
+		return models.CourseSchedule{}, err
+	}
+
+	err := s.saveSchedule(schedule, requester, func(tx database.Transaction) error {
+		return s.scheduleRepository.UpdateSchedule(tx, schedule)
+	})
+	if err != nil {
+		return models.CourseSchedule{}, err
+	}
+	return s.scheduleRepository.GetCourseSchedule(schedule.CourseID, schedule.ID)
+}
+
+func (s *ScheduleService) DeleteSchedule(courseID uuid.UUID, scheduleID uuid.UUID, requester models.Requester) error {
+	tx, err := s.scheduleRepository.BeginTransaction()
+	if err != nil {
+		return err
+	}
+	center, err := s.scheduleRepository.LockCourseCenter(tx, courseID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := CheckOwnership(requester, center.OwnerID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := s.scheduleRepository.DeleteSchedule(tx, courseID, scheduleID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// saveSchedule checks owner of course and conflicts of schedule with other groups of the center and then saves it.
+// Center stays locked from the check till commit, so concurrent saves can't book the same room or teacher twice.
+func (s *ScheduleService) saveSchedule(schedule models.CourseScheduleDto, requester models.Requester, save func(tx database.Transaction) error) error {
+	tx, err := s.scheduleRepository.BeginTransaction()
+	if err != nil {
+		return err
+	}
+	center, err := s.scheduleRepository.LockCourseCenter(tx, schedule.CourseID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := CheckOwnership(requester, center.OwnerID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	conflicts, err := s.scheduleRepository.FindConflicts(tx, center, schedule)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if len(conflicts) > 0 {
+		tx.Rollback()
+		return scheduleConflictError(conflicts[0])
+	}
+
+	if err := save(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func scheduleConflictError(conflict models.ScheduleConflict) error {
+	shared := "teacher"
+	if conflict.SameRoom {
+		shared = "room"
+	}
+	return fmt.Errorf("%s : %s", custom_errors.ErrValidation, fmt.Sprintf("slot overlaps %s %s-%s of group %q of course %q with the same %s",
+		time.Weekday(conflict.DayOfWeek), conflict.StartTime, conflict.EndTime, conflict.ScheduleName, conflict.CourseName, shared))
+}
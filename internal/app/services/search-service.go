@@ -0,0 +1,91 @@
+package services
+
+import (
+	"edumatch/internal/app/models"
+	"edumatch/internal/app/repositories"
+	"edumatch/internal/app/validators"
+	"sort"
+	"strings"
+)
+
+type SearchServiceInterface interface {
+	Search(query models.SearchQuery) (models.SearchResults, error)
+	Suggest(query models.SuggestQuery) (models.Suggestions, error)
+}
+
+// order of suggestion types matching prefix equally well
+var suggestionTypeOrder = map[string]int{
+	models.SearchTypeEduCenter:    0,
+	models.SearchTypeCourse:       1,
+	models.SuggestionTypeTeacher:  2,
+	models.SuggestionTypeDistrict: 3,
+}
+
+type SearchService struct {
+	searchRepository repositories.SearchRepositoryInterface
+	validator        validators.SearchValidatorInterface
+	geocoder         GeocoderInterface
+}
+
+func NewSearchService(searchRepository repositories.SearchRepositoryInterface, searchValidator validators.SearchValidatorInterface, geocoder GeocoderInterface) SearchServiceInterface {
+	return &SearchService{
+		searchRepository: searchRepository,
+		validator:        searchValidator,
+		geocoder:         geocoder,
+	}
+}
+
+func (s *SearchService) Search(query models.SearchQuery) (models.SearchResults, error) {
+	//validate query
+	query.Text = strings.TrimSpace(query.Text)
+	if err := s.validator.ValidateSearchQuery(&query); err != nil {
+		return models.SearchResults{}, err
+	}
+	NormalizePageQuery(&query.PageQuery, models.OrderDesc)
+
+	results, err := s.searchRepository.Search(query, false)
+	if err != nil {
+		return models.SearchResults{}, err
+	}
+	//no word matched exactly, text is probably misspelled
+	if results.Count == 0 {
+		results, err = s.searchRepository.Search(query, true)
+		if err != nil {
+			return models.SearchResults{}, err
+		}
+	}
+	results.Pagination = ResponsePagination(query.PageQuery, results.NextCursor)
+
+	return results, nil
+}
+
+func (s *SearchService) Suggest(query models.SuggestQuery) (models.Suggestions, error) {
+	//validate query
+	query.Prefix = strings.TrimSpace(query.Prefix)
+	if err := s.validator.ValidateSuggestQuery(&query); err != nil {
+		return models.Suggestions{}, err
+	}
+	if query.Limit <= 0 {
+		query.Limit = models.DefaultSuggestLimit
+	}
+
+	suggestions, err := s.searchRepository.Suggest(query.Prefix, query.Limit)
+	if err != nil {
+		return models.Suggestions{}, err
+	}
+	districts, err := s.geocoder.SuggestPlaces(query.Prefix, models.PlaceDistrict, query.Limit)
+	if err != nil {
+		return models.Suggestions{}, err
+	}
+	suggestions = append(suggestions, districts...)
+
+	//each type is already ranked, types are mixed by how well they match prefix
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Match != suggestions[j].Match {
+			return suggestions[i].Match < suggestions[j].Match
+		}
+		return suggestionTypeOrder[suggestions[i].Type] < suggestionTypeOrder[suggestions[j].Type]
+	})
+
+	return models.Suggestions{Suggestions: suggestions}, nil
+}
@@ -1,11 +1,16 @@
 package services
 
 import (
+	custom_errors "edumatch/internal/app/errors"
 	"edumatch/internal/app/models"
 	"edumatch/internal/app/repositories"
 	"edumatch/internal/app/validators"
+	"edumatch/pkg/mailer"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"go.uber.org/zap"
 )
 
 type UserServiceInterface interface {
@@ -14,19 +19,28 @@ type UserServiceInterface interface {
 	GetUser(userID uuid.UUID) (models.User, error)
 	GetUserByEmail(email string) (models.User, error)
 	GetUserByUsername(username string) (models.User, error)
-	UpdateUser(user models.UpdateUserDto) (models.User, error)
-	DeleteUser(userID uuid.UUID) error
+	UpdateUser(user models.UpdateUserDto, requester models.Requester) (models.User, error)
+	DeleteUser(userID uuid.UUID, requester models.Requester) error
+	SendVerificationEmail(userID uuid.UUID) error
+	VerifyEmail(token string) error
 }
 
 type UserService struct {
 	userRepository repositories.UserRepositoryInterface
 	validator      validators.UserValidatorInterface
+	mailer         mailer.Mailer
+	// link of email verification endpoint or client page, token is added to its query
+	emailVerificationURL string
+	logger               *zap.Logger
 }
 
-func NewUserService(userRepository repositories.UserRepositoryInterface, userValidator validators.UserValidatorInterface) UserServiceInterface {
+func NewUserService(userRepository repositories.UserRepositoryInterface, userValidator validators.UserValidatorInterface, mailer mailer.Mailer, emailVerificationURL string, logger *zap.Logger) UserServiceInterface {
 	return &UserService{
-		userRepository: userRepository,
-		validator:      userValidator,
+		userRepository:       userRepository,
+		validator:            userValidator,
+		mailer:               mailer,
+		emailVerificationURL: emailVerificationURL,
+		logger:               logger,
 	}
 }
 
@@ -85,11 +99,19 @@ func (s *UserService) GetUserByUsername(username string) (models.User, error) {
 	}
 	return user, nil
 }
-func (s *UserService) UpdateUser(user models.UpdateUserDto) (models.User, error) {
+func (s *UserService) UpdateUser(user models.UpdateUserDto, requester models.Requester) (models.User, error) {
+	//users can update only themselves
+	if err := CheckOwnership(requester, user.ID); err != nil {
+		return models.User{}, err
+	}
 	//validate before update
 	if err := s.validator.ValidateUserUpdate(&user); err != nil {
 		return models.User{}, err
 	}
+	currentUser, err := s.userRepository.GetUser(user.ID)
+	if err != nil {
+		return models.User{}, err
+	}
 	//if there is no avatar keep old one
 	user.AvatarUrl = user.OldAvatar
 
@@ -106,12 +128,70 @@ func (s *UserService) UpdateUser(user models.UpdateUserDto) (models.User, error)
 		return models.User{}, err
 	}
 
+	//new email is unverified till user opens link mailed to it, the update is already saved so failure to send
+	//the link is only logged and user can ask for it again
+	if updatedUser.Email != "" && updatedUser.Email != currentUser.Email {
+		if err := s.SendVerificationEmail(updatedUser.ID); err != nil {
+			s.logger.Error("Failed to send verification email", zap.String("user_id", updatedUser.ID.String()), zap.Error(err))
+		}
+	}
+
 	return updatedUser, nil
 }
 
-func (s *UserService) DeleteUser(userID uuid.UUID) error {
+func (s *UserService) DeleteUser(userID uuid.UUID, requester models.Requester) error {
+	if err := CheckOwnership(requester, userID); err != nil {
+		return err
+	}
 	if err := s.userRepository.DeleteUser(userID); err != nil {
 		return err
 	}
 	return nil
 }
+
+// SendVerificationEmail mails link verifying email of user, it is sent again only after resend interval
+func (s *UserService) SendVerificationEmail(userID uuid.UUID) error {
+	user, err := s.userRepository.GetUser(userID)
+	if err != nil {
+		return err
+	}
+	if user.Email == "" {
+		return custom_errors.ErrEmailNotSet
+	}
+	if user.EmailVerified {
+		return custom_errors.ErrEmailAlreadyVerified
+	}
+	marked, err := s.userRepository.MarkVerificationSent(user.ID, VerificationResendInterval())
+	if err != nil {
+		return err
+	}
+	if !marked {
+		return custom_errors.ErrVerificationTooSoon
+	}
+
+	lifetime := EmailVerificationLifetime()
+	token, err := GenerateEmailVerificationToken(user.ID, user.Email, time.Now().Add(lifetime))
+	if err != nil {
+		return err
+	}
+	link, err := LinkWithToken(s.emailVerificationURL, token)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your EduMatch email",
+		Body: fmt.Sprintf("Hello %s,\n\nOpen the link below within %d hours to confirm this email of your EduMatch account %s:\n\n%s\n\n"+
+			"If you didn't add this email to EduMatch, ignore this message.\n",
+			user.FirstName, int(lifetime.Hours()), user.Username, link),
+	})
+}
+
+// VerifyEmail marks email of signed link verified if it is still the email of user
+func (s *UserService) VerifyEmail(token string) error {
+	claims, err := ValidateEmailVerificationToken(token)
+	if err != nil {
+		return err
+	}
+	return s.userRepository.VerifyEmail(claims.UserID, claims.Email)
+}
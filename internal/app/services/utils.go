@@ -1,12 +1,17 @@
 package services
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
 	custom_errors "edumatch/internal/app/errors"
 	"edumatch/internal/app/models"
 	"edumatch/internal/config"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"io/ioutil"
 	"mime/multipart"
+	"net/url"
 	"os"
 	"path"
 	"strconv"
@@ -26,26 +31,124 @@ func HashPassword(password string) (string, error) {
 	return string(hashedPassword), err
 }
 
-func GenerateToken(userID uuid.UUID, role models.Role, isRefreshToken bool) (string, error) {
-	expTimeKey := "JWT_EXP_TIME"
-	secretKey := "JWT_SECRET"
-
-	if isRefreshToken {
-		expTimeKey = "JWT_REFRESH_EXP_TIME"
-		secretKey = "JWT_REFRESH_SECRET"
-	}
-	expTime, _ := strconv.Atoi(config.GetEnv(expTimeKey, "24"))
+// GenerateToken issues access token of user in session, it is accepted while session isn't terminated
+func GenerateToken(userID uuid.UUID, role models.Role, sessionID uuid.UUID) (string, error) {
+	expTime, _ := strconv.Atoi(config.GetEnv("JWT_EXP_TIME", "24"))
 
 	// Define the claims for the token
 	claims := jwt.MapClaims{
 		"user_id": userID,
 		"role":    role,
+		"sid":     sessionID,
 		"exp":     time.Now().Add(time.Hour * 24 * time.Duration(expTime)).Unix(),
 	}
+	return signToken(claims, "JWT_SECRET")
+}
+
+// GenerateRefreshToken issues refresh token identified by its record in refresh tokens, role isn't kept in it
+// as it is read again from user when tokens are refreshed
+func GenerateRefreshToken(userID uuid.UUID, tokenID uuid.UUID, expiresAt time.Time) (string, error) {
+	claims := jwt.MapClaims{
+		"user_id": userID,
+		"jti":     tokenID,
+		"exp":     expiresAt.Unix(),
+	}
+	return signToken(claims, "JWT_REFRESH_SECRET")
+}
+
+// RefreshTokenLifetime returns lifetime of refresh tokens configured in days
+func RefreshTokenLifetime() time.Duration {
+	expTime, _ := strconv.Atoi(config.GetEnv("JWT_REFRESH_EXP_TIME", "24"))
+	return time.Hour * 24 * time.Duration(expTime)
+}
+
+// PasswordResetLifetime returns lifetime of password reset tokens configured in minutes
+func PasswordResetLifetime() time.Duration {
+	expTime, _ := strconv.Atoi(config.GetEnv("PASSWORD_RESET_EXP_TIME", "60"))
+	return time.Minute * time.Duration(expTime)
+}
+
+// GenerateEmailVerificationToken signs link token confirming email of user, it verifies nothing once user changes the email
+func GenerateEmailVerificationToken(userID uuid.UUID, email string, expiresAt time.Time) (string, error) {
+	claims := jwt.MapClaims{
+		"user_id": userID,
+		"email":   email,
+		"exp":     expiresAt.Unix(),
+	}
+	return signToken(claims, "JWT_EMAIL_SECRET")
+}
+
+// ValidateEmailVerificationToken validates signature and expiry of email verification token
+func ValidateEmailVerificationToken(tokenString string) (models.EmailVerificationClaims, error) {
+	claims, err := parseToken(tokenString, "JWT_EMAIL_SECRET")
+	if err != nil {
+		return models.EmailVerificationClaims{}, custom_errors.ErrInvalidVerificationToken
+	}
+	userID, err := uuidClaim(claims, "user_id")
+	if err != nil {
+		return models.EmailVerificationClaims{}, custom_errors.ErrInvalidVerificationToken
+	}
+	email, ok := claims["email"].(string)
+	if !ok || email == "" {
+		return models.EmailVerificationClaims{}, custom_errors.ErrInvalidVerificationToken
+	}
+	return models.EmailVerificationClaims{UserID: userID, Email: email}, nil
+}
 
+// EmailVerificationLifetime returns lifetime of email verification links configured in hours
+func EmailVerificationLifetime() time.Duration {
+	expTime, _ := strconv.Atoi(config.GetEnv("EMAIL_VERIFICATION_EXP_TIME", "24"))
+	return time.Hour * time.Duration(expTime)
+}
+
+// VerificationResendInterval returns how long user waits before verification link is sent again, configured in seconds
+func VerificationResendInterval() time.Duration {
+	interval, _ := strconv.Atoi(config.GetEnv("EMAIL_VERIFICATION_RESEND_INTERVAL", "60"))
+	return time.Second * time.Duration(interval)
+}
+
+// GenerateSecretToken returns random url safe token mailed to user and its hash which is stored instead of it
+func GenerateSecretToken() (string, string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(bytes)
+	return token, HashSecretToken(token), nil
+}
+
+// LinkWithToken adds token to query of mailed link
+func LinkWithToken(link string, token string) (string, error) {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+	query := parsed.Query()
+	query.Set("token", token)
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), nil
+}
+
+// HashSecretToken returns hex encoded sha-256 of token, tokens are random enough to need no salt
+func HashSecretToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// tokenSecret returns secret configured by env key. Unset email verification secret is derived from access token
+// secret, so links aren't signed with the default one and can't be used as access tokens.
+func tokenSecret(secretKey string) string {
+	if secretKey == "JWT_EMAIL_SECRET" && config.GetEnv(secretKey, "") == "" {
+		hash := sha256.Sum256([]byte("email-verification:" + config.GetEnv("JWT_SECRET", "nothing")))
+		return hex.EncodeToString(hash[:])
+	}
+	return config.GetEnv(secretKey, "nothing")
+}
+
+func signToken(claims jwt.MapClaims, secretKey string) (string, error) {
 	// Generate the token
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	secretToken := config.GetEnv(secretKey, "nothing")
+	secretToken := tokenSecret(secretKey)
 	signedToken, err := token.SignedString([]byte(secretToken))
 	if err != nil {
 		return "", err
@@ -54,53 +157,120 @@ func GenerateToken(userID uuid.UUID, role models.Role, isRefreshToken bool) (str
 	return signedToken, nil
 }
 
-func ValidateToken(tokenString string, isRefreshToken bool) (uuid.UUID, models.Role, error) {
+// ValidateToken validates access token and returns its user, role and session
+func ValidateToken(tokenString string) (models.AccessClaims, error) {
+	claims, err := parseToken(tokenString, "JWT_SECRET")
+	if err != nil {
+		return models.AccessClaims{}, err
+	}
+
+	var userRole models.Role
+	switch roleClaim := claims["role"].(type) {
+	case string:
+		userRole = models.Role(roleClaim)
+	default:
+		return models.AccessClaims{}, custom_errors.ErrInvalidToken
+	}
+
+	userID, err := uuidClaim(claims, "user_id")
+	if err != nil {
+		return models.AccessClaims{}, err
+	}
+	//tokens issued before sessions were recorded can't be terminated, so they aren't accepted
+	sessionID, err := uuidClaim(claims, "sid")
+	if err != nil {
+		return models.AccessClaims{}, err
+	}
+
+	return models.AccessClaims{UserID: userID, Role: userRole, SessionID: sessionID}, nil
+}
+
+// ValidateRefreshToken validates signature and expiry of refresh token, its record is checked by caller
+func ValidateRefreshToken(tokenString string) (models.RefreshClaims, error) {
+	claims, err := parseToken(tokenString, "JWT_REFRESH_SECRET")
+	if err != nil {
+		return models.RefreshClaims{}, err
+	}
+
+	userID, err := uuidClaim(claims, "user_id")
+	if err != nil {
+		return models.RefreshClaims{}, err
+	}
+	//tokens issued before refresh tokens were recorded have no id
+	tokenID, err := uuidClaim(claims, "jti")
+	if err != nil {
+		return models.RefreshClaims{}, err
+	}
+
+	return models.RefreshClaims{TokenID: tokenID, UserID: userID}, nil
+}
+
+func parseToken(tokenString string, secretKey string) (jwt.MapClaims, error) {
 	// Remove the "Bearer " prefix if it exists
 	tokenString = strings.TrimPrefix(tokenString, "Bearer ")
 
-	var secretKey string
-	if isRefreshToken {
-		secretKey = config.GetEnv("JWT_REFRESH_SECRET", "nothing")
-	} else {
-		secretKey = config.GetEnv("JWT_SECRET", "nothing")
-	}
+	secret := tokenSecret(secretKey)
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		// Validate the signing method used
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, custom_errors.ErrInvalidToken
 		}
 
-		return []byte(secretKey), nil
+		return []byte(secret), nil
 	})
 
 	if err != nil {
-		return uuid.Nil, "", custom_errors.ErrInvalidToken
+		return nil, custom_errors.ErrInvalidToken
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok || !token.Valid {
-		return uuid.Nil, "", custom_errors.ErrInvalidToken
+		return nil, custom_errors.ErrInvalidToken
 	}
+	return claims, nil
+}
 
-	userIDStr, ok := claims["user_id"].(string)
+func uuidClaim(claims jwt.MapClaims, key string) (uuid.UUID, error) {
+	value, ok := claims[key].(string)
 	if !ok {
-		return uuid.Nil, "", custom_errors.ErrInvalidToken
+		return uuid.Nil, custom_errors.ErrInvalidToken
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, custom_errors.ErrInvalidToken
 	}
+	return id, nil
+}
 
-	var userRole models.Role
-	switch roleClaim := claims["role"].(type) {
-	case string:
-		userRole = models.Role(roleClaim)
-	default:
-		return uuid.Nil, "", custom_errors.ErrInvalidToken
+// NormalizePageQuery fills default paging and sort order of listing
+func NormalizePageQuery(query *models.PageQuery, defaultOrder string) {
+	if query.Limit <= 0 {
+		query.Limit = models.DefaultPageLimit
+	}
+	if query.Page <= 0 {
+		query.Page = 1
 	}
+	if query.Order == "" {
+		query.Order = defaultOrder
+	}
+}
 
-	userID, err := uuid.Parse(userIDStr)
-	if err != nil {
-		return uuid.Nil, "", custom_errors.ErrInvalidToken
+// ResponsePagination returns paging metadata of listing response
+func ResponsePagination(query models.PageQuery, nextCursor string) models.Pagination {
+	pagination := models.Pagination{Limit: query.Limit, NextCursor: nextCursor}
+	//page number has no meaning for cursor pagination
+	if query.Cursor == "" {
+		pagination.Page = query.Page
 	}
+	return pagination
+}
 
-	return userID, userRole, nil
+// CheckOwnership allows only owner of resource or admin to modify it
+func CheckOwnership(requester models.Requester, ownerID uuid.UUID) error {
+	if requester.Role == models.AdminRole || requester.UserID == ownerID {
+		return nil
+	}
+	return custom_errors.ErrForbidden
 }
 
 func CheckPassword(hashedPassword, plainPassword string) bool {
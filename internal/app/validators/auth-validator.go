@@ -0,0 +1,51 @@
+package validators
+
+import (
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type AuthValidatorInterface interface {
+	ValidateChangePassword(dto *models.ChangePasswordDto) error
+	ValidateForgotPassword(dto *models.ForgotPasswordDto) error
+	ValidateResetPassword(dto *models.ResetPasswordDto) error
+}
+
+type AuthValidator struct {
+	validate *validator.Validate
+}
+
+func NewAuthValidator() AuthValidatorInterface {
+	return &AuthValidator{
+		validate: validator.New(),
+	}
+}
+
+func (v *AuthValidator) ValidateChangePassword(dto *models.ChangePasswordDto) error {
+	return v.validateStruct(dto)
+}
+
+func (v *AuthValidator) ValidateForgotPassword(dto *models.ForgotPasswordDto) error {
+	return v.validateStruct(dto)
+}
+
+func (v *AuthValidator) ValidateResetPassword(dto *models.ResetPasswordDto) error {
+	return v.validateStruct(dto)
+}
+
+func (v *AuthValidator) validateStruct(s interface{}) error {
+	err := v.validate.Struct(s)
+	if err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+
+		return fmt.Errorf("%s : %v", custom_errors.ErrValidation, validationErrors)
+	}
+
+	return nil
+}
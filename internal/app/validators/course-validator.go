@@ -0,0 +1,88 @@
+package validators
+
+import (
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type CourseValidatorInterface interface {
+	ValidateCourseQuery(query *models.CourseQuery) error
+	ValidateCourseRating(rating *models.CourseRating) error
+	ValidateCoursePrice(price *models.CoursePriceDto) error
+	ValidateCourseDiscount(discount *models.CourseDiscountDto) error
+}
+
+type CourseValidator struct {
+	validate *validator.Validate
+}
+
+func NewCourseValidator() CourseValidatorInterface {
+	return &CourseValidator{
+		validate: validator.New(),
+	}
+}
+
+func (v *CourseValidator) ValidateCourseQuery(query *models.CourseQuery) error {
+	err := v.validate.Struct(query)
+	if err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+
+		return fmt.Errorf("%s : %v", custom_errors.ErrValidation, validationErrors)
+	}
+	if query.TimeFrom != "" && query.TimeTo != "" && query.TimeFrom >= query.TimeTo {
+		return fmt.Errorf("%s : %s", custom_errors.ErrValidation, "time_from must be before time_to")
+	}
+	if query.SortBy == models.CourseSortPrice && (query.Currency == "" || query.PriceUnit == "") {
+		return fmt.Errorf("%s : %s", custom_errors.ErrValidation, "currency and price_unit are required to sort by price")
+	}
+
+	return nil
+}
+
+func (v *CourseValidator) ValidateCourseRating(rating *models.CourseRating) error {
+	err := v.validate.Struct(rating)
+	if err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+
+		return fmt.Errorf("%s : %v", custom_errors.ErrValidation, validationErrors)
+	}
+
+	return nil
+}
+
+func (v *CourseValidator) ValidateCoursePrice(price *models.CoursePriceDto) error {
+	err := v.validate.Struct(price)
+	if err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+
+		return fmt.Errorf("%s : %v", custom_errors.ErrValidation, validationErrors)
+	}
+
+	return nil
+}
+
+func (v *CourseValidator) ValidateCourseDiscount(discount *models.CourseDiscountDto) error {
+	err := v.validate.Struct(discount)
+	if err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+
+		return fmt.Errorf("%s : %v", custom_errors.ErrValidation, validationErrors)
+	}
+
+	return nil
+}
@@ -10,6 +10,10 @@ import (
 
 type EduCenterValidatorInterface interface {
 	ValidateEduCenterCreate(product *models.CreateEduCenterDto) error
+	ValidateEduCenterQuery(query *models.EduCenterQuery) error
+	ValidateEduCenterRating(rating *models.EduCenterRating) error
+	ValidateNearEduCenter(location *models.NearEduCenterDto) error
+	ValidateEduCenterMapQuery(query *models.EduCenterMapQuery) error
 }
 
 type EduCenterValidator struct {
@@ -35,3 +39,63 @@ func (v *EduCenterValidator) ValidateEduCenterCreate(product *models.CreateEduCe
 
 	return nil
 }
+
+func (v *EduCenterValidator) ValidateEduCenterQuery(query *models.EduCenterQuery) error {
+	err := v.validate.Struct(query)
+	if err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+
+		return fmt.Errorf("%s : %v", custom_errors.ErrValidation, validationErrors)
+	}
+	//distance can be calculated only from given point
+	if query.SortBy == models.EduCenterSortDistance && query.Latitude == nil {
+		return fmt.Errorf("%s : %s", custom_errors.ErrValidation, "latitude and longitude are required to sort by distance")
+	}
+
+	return nil
+}
+
+func (v *EduCenterValidator) ValidateEduCenterRating(rating *models.EduCenterRating) error {
+	err := v.validate.Struct(rating)
+	if err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+
+		return fmt.Errorf("%s : %v", custom_errors.ErrValidation, validationErrors)
+	}
+
+	return nil
+}
+
+func (v *EduCenterValidator) ValidateNearEduCenter(location *models.NearEduCenterDto) error {
+	err := v.validate.Struct(location)
+	if err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+
+		return fmt.Errorf("%s : %v", custom_errors.ErrValidation, validationErrors)
+	}
+
+	return nil
+}
+
+func (v *EduCenterValidator) ValidateEduCenterMapQuery(query *models.EduCenterMapQuery) error {
+	err := v.validate.Struct(query)
+	if err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+
+		return fmt.Errorf("%s : %v", custom_errors.ErrValidation, validationErrors)
+	}
+
+	return nil
+}
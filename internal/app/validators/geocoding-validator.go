@@ -0,0 +1,37 @@
+package validators
+
+import (
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type GeocodingValidatorInterface interface {
+	ValidateReverseGeocodeQuery(query *models.ReverseGeocodeQuery) error
+}
+
+type GeocodingValidator struct {
+	validate *validator.Validate
+}
+
+func NewGeocodingValidator() GeocodingValidatorInterface {
+	return &GeocodingValidator{
+		validate: validator.New(),
+	}
+}
+
+func (v *GeocodingValidator) ValidateReverseGeocodeQuery(query *models.ReverseGeocodeQuery) error {
+	err := v.validate.Struct(query)
+	if err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+
+		return fmt.Errorf("%s : %v", custom_errors.ErrValidation, validationErrors)
+	}
+
+	return nil
+}
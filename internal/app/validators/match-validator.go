@@ -0,0 +1,63 @@
+package validators
+
+import (
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type MatchValidatorInterface interface {
+	ValidateStudentProfile(profile *models.StudentProfileDto) error
+	ValidateMatchQuery(query *models.MatchQuery) error
+}
+
+type MatchValidator struct {
+	validate *validator.Validate
+}
+
+func NewMatchValidator() MatchValidatorInterface {
+	return &MatchValidator{
+		validate: validator.New(),
+	}
+}
+
+func (v *MatchValidator) ValidateStudentProfile(profile *models.StudentProfileDto) error {
+	if err := v.validateStruct(profile); err != nil {
+		return err
+	}
+	if location := profile.HomeLocation; location != nil {
+		if location.Latitude < -90 || location.Latitude > 90 || location.Longitude < -180 || location.Longitude > 180 {
+			return fmt.Errorf("%s : %s", custom_errors.ErrValidation, "home location is out of range")
+		}
+	}
+	//distance can be measured only from home
+	if profile.MaxDistance != nil && profile.HomeLocation == nil {
+		return fmt.Errorf("%s : %s", custom_errors.ErrValidation, "home location is required for max distance")
+	}
+	//times are zero padded, so they are compared as strings
+	if profile.PreferredTimeFrom != "" && profile.PreferredTimeFrom >= profile.PreferredTimeTo {
+		return fmt.Errorf("%s : %s", custom_errors.ErrValidation, "preferred time from must be before preferred time to")
+	}
+
+	return nil
+}
+
+func (v *MatchValidator) ValidateMatchQuery(query *models.MatchQuery) error {
+	return v.validateStruct(query)
+}
+
+func (v *MatchValidator) validateStruct(s interface{}) error {
+	err := v.validate.Struct(s)
+	if err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+
+		return fmt.Errorf("%s : %v", custom_errors.ErrValidation, validationErrors)
+	}
+
+	return nil
+}
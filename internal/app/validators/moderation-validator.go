@@ -0,0 +1,51 @@
+package validators
+
+import (
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type ModerationValidatorInterface interface {
+	ValidateReport(report *models.CreateReportDto) error
+	ValidateDecision(decision *models.ModerationDecisionDto) error
+	ValidateReportQuery(query *models.ReportQuery) error
+}
+
+type ModerationValidator struct {
+	validate *validator.Validate
+}
+
+func NewModerationValidator() ModerationValidatorInterface {
+	return &ModerationValidator{
+		validate: validator.New(),
+	}
+}
+
+func (v *ModerationValidator) ValidateReport(report *models.CreateReportDto) error {
+	return v.validateStruct(report)
+}
+
+func (v *ModerationValidator) ValidateDecision(decision *models.ModerationDecisionDto) error {
+	return v.validateStruct(decision)
+}
+
+func (v *ModerationValidator) ValidateReportQuery(query *models.ReportQuery) error {
+	return v.validateStruct(query)
+}
+
+func (v *ModerationValidator) validateStruct(s interface{}) error {
+	err := v.validate.Struct(s)
+	if err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+
+		return fmt.Errorf("%s : %v", custom_errors.ErrValidation, validationErrors)
+	}
+
+	return nil
+}
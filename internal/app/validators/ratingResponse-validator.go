@@ -0,0 +1,51 @@
+package validators
+
+import (
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type RatingResponseValidatorInterface interface {
+	ValidateResponse(response *models.RatingResponseDto) error
+	ValidateUnansweredQuery(query *models.UnansweredRatingsQuery) error
+	ValidatePageQuery(query *models.PageQuery) error
+}
+
+type RatingResponseValidator struct {
+	validate *validator.Validate
+}
+
+func NewRatingResponseValidator() RatingResponseValidatorInterface {
+	return &RatingResponseValidator{
+		validate: validator.New(),
+	}
+}
+
+func (v *RatingResponseValidator) ValidateResponse(response *models.RatingResponseDto) error {
+	return v.validateStruct(response)
+}
+
+func (v *RatingResponseValidator) ValidateUnansweredQuery(query *models.UnansweredRatingsQuery) error {
+	return v.validateStruct(query)
+}
+
+func (v *RatingResponseValidator) ValidatePageQuery(query *models.PageQuery) error {
+	return v.validateStruct(query)
+}
+
+func (v *RatingResponseValidator) validateStruct(s interface{}) error {
+	err := v.validate.Struct(s)
+	if err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+
+		return fmt.Errorf("%s : %v", custom_errors.ErrValidation, validationErrors)
+	}
+
+	return nil
+}
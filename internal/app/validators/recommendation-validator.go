@@ -0,0 +1,46 @@
+package validators
+
+import (
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type RecommendationValidatorInterface interface {
+	ValidateRecommendationQuery(query *models.RecommendationQuery) error
+	ValidateSimilarQuery(query *models.SimilarQuery) error
+}
+
+type RecommendationValidator struct {
+	validate *validator.Validate
+}
+
+func NewRecommendationValidator() RecommendationValidatorInterface {
+	return &RecommendationValidator{
+		validate: validator.New(),
+	}
+}
+
+func (v *RecommendationValidator) ValidateRecommendationQuery(query *models.RecommendationQuery) error {
+	return v.validateStruct(query)
+}
+
+func (v *RecommendationValidator) ValidateSimilarQuery(query *models.SimilarQuery) error {
+	return v.validateStruct(query)
+}
+
+func (v *RecommendationValidator) validateStruct(s interface{}) error {
+	err := v.validate.Struct(s)
+	if err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+
+		return fmt.Errorf("%s : %v", custom_errors.ErrValidation, validationErrors)
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package validators
+
+import (
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type ReviewValidatorInterface interface {
+	ValidateReview(review *models.ReviewDto) error
+	ValidateReviewQuery(query *models.ReviewQuery) error
+}
+
+type ReviewValidator struct {
+	validate *validator.Validate
+}
+
+func NewReviewValidator() ReviewValidatorInterface {
+	return &ReviewValidator{
+		validate: validator.New(),
+	}
+}
+
+func (v *ReviewValidator) ValidateReview(review *models.ReviewDto) error {
+	return v.validateStruct(review)
+}
+
+func (v *ReviewValidator) ValidateReviewQuery(query *models.ReviewQuery) error {
+	return v.validateStruct(query)
+}
+
+func (v *ReviewValidator) validateStruct(s interface{}) error {
+	err := v.validate.Struct(s)
+	if err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+
+		return fmt.Errorf("%s : %v", custom_errors.ErrValidation, validationErrors)
+	}
+
+	return nil
+}
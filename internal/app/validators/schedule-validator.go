@@ -0,0 +1,53 @@
+package validators
+
+import (
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type ScheduleValidatorInterface interface {
+	ValidateSchedule(schedule *models.CourseScheduleDto) error
+}
+
+type ScheduleValidator struct {
+	validate *validator.Validate
+}
+
+func NewScheduleValidator() ScheduleValidatorInterface {
+	return &ScheduleValidator{
+		validate: validator.New(),
+	}
+}
+
+func (v *ScheduleValidator) ValidateSchedule(schedule *models.CourseScheduleDto) error {
+	err := v.validate.Struct(schedule)
+	if err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+
+		return fmt.Errorf("%s : %v", custom_errors.ErrValidation, validationErrors)
+	}
+	//dates and times are zero padded, so they are compared as strings
+	if schedule.EndDate != "" && schedule.EndDate < schedule.StartDate {
+		return fmt.Errorf("%s : %s", custom_errors.ErrValidation, "end date must not be before start date")
+	}
+	for i, slot := range schedule.Slots {
+		if slot.StartTime >= slot.EndTime {
+			return fmt.Errorf("%s : %s", custom_errors.ErrValidation, "slot must start before it ends")
+		}
+		for _, other := range schedule.Slots[:i] {
+			if *slot.DayOfWeek == *other.DayOfWeek && slot.StartTime < other.EndTime && other.StartTime < slot.EndTime {
+				return fmt.Errorf("%s : slots on %s %s-%s and %s-%s overlap", custom_errors.ErrValidation,
+					time.Weekday(*slot.DayOfWeek), other.StartTime, other.EndTime, slot.StartTime, slot.EndTime)
+			}
+		}
+	}
+
+	return nil
+}
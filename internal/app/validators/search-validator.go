@@ -0,0 +1,56 @@
+package validators
+
+import (
+	custom_errors "edumatch/internal/app/errors"
+	"edumatch/internal/app/models"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type SearchValidatorInterface interface {
+	ValidateSearchQuery(query *models.SearchQuery) error
+	ValidateSuggestQuery(query *models.SuggestQuery) error
+}
+
+type SearchValidator struct {
+	validate *validator.Validate
+}
+
+func NewSearchValidator() SearchValidatorInterface {
+	return &SearchValidator{
+		validate: validator.New(),
+	}
+}
+
+func (v *SearchValidator) ValidateSearchQuery(query *models.SearchQuery) error {
+	err := v.validate.Struct(query)
+	if err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+
+		return fmt.Errorf("%s : %v", custom_errors.ErrValidation, validationErrors)
+	}
+	//radius can be applied only around given point
+	if query.Distance > 0 && query.Latitude == nil {
+		return fmt.Errorf("%s : %s", custom_errors.ErrValidation, "latitude and longitude are required to search within distance")
+	}
+
+	return nil
+}
+
+func (v *SearchValidator) ValidateSuggestQuery(query *models.SuggestQuery) error {
+	err := v.validate.Struct(query)
+	if err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+
+		return fmt.Errorf("%s : %v", custom_errors.ErrValidation, validationErrors)
+	}
+
+	return nil
+}
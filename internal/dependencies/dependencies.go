@@ -3,27 +3,43 @@ package dependencies
 import (
 	custom_errors "edumatch/internal/app/errors"
 	"edumatch/internal/app/handlers"
+	"edumatch/internal/app/jobs"
 	"edumatch/internal/app/repositories"
 	"edumatch/internal/app/services"
 	"edumatch/internal/app/validators"
+	"edumatch/internal/config"
 	database "edumatch/pkg/db"
 	"edumatch/pkg/logger"
+	"edumatch/pkg/mailer"
 	"fmt"
+	"strconv"
+	"time"
+	// time zones are embedded, so calendars work without system tz database
+	_ "time/tzdata"
 
 	"go.uber.org/zap"
 )
 
 type Handlers struct {
-	UserHandler      *handlers.UserHandler
-	EduCenterHandler handlers.EduCenterHandlerInterface
-	AuthHandler      handlers.AuthHandlerInterface
-	CourseHandler    handlers.CourseHandlerInterface
+	UserHandler           *handlers.UserHandler
+	EduCenterHandler      handlers.EduCenterHandlerInterface
+	AuthHandler           handlers.AuthHandlerInterface
+	CourseHandler         handlers.CourseHandlerInterface
+	ReviewHandler         handlers.ReviewHandlerInterface
+	ModerationHandler     handlers.ModerationHandlerInterface
+	RatingResponseHandler handlers.RatingResponseHandlerInterface
+	GeocodingHandler      handlers.GeocodingHandlerInterface
+	SearchHandler         handlers.SearchHandlerInterface
+	MatchHandler          handlers.MatchHandlerInterface
+	RecommendationHandler handlers.RecommendationHandlerInterface
+	ScheduleHandler       handlers.ScheduleHandlerInterface
 }
 
 // Application struct holds references to all the handlers.
 type Application struct {
 	GlobalErrorHandler *custom_errors.GlobalErrorHandler
 	Handlers           Handlers
+	Jobs               []jobs.JobInterface
 	Logger             *zap.Logger
 }
 
@@ -43,22 +59,96 @@ func InitDependencies() (*Application, error) {
 	userRepository := repositories.NewUserRepository(db)
 	eduCenterRepository := repositories.NewEduCenterRepository(db)
 	courseRepasitory := repositories.NewCourseRepository(db)
+	reviewRepository := repositories.NewReviewRepository(db)
+	moderationRepository := repositories.NewModerationRepository(db)
+	ratingResponseRepository := repositories.NewRatingResponseRepository(db)
+	searchRepository := repositories.NewSearchRepository(db)
+	matchRepository := repositories.NewMatchRepository(db)
+	recommendationRepository := repositories.NewRecommendationRepository(db)
+	scheduleRepository := repositories.NewScheduleRepository(db)
+	tokenRepository := repositories.NewTokenRepository(db)
 
 	//INITIALIZE VALIDATORS
 	userValidator := validators.NewUserValidator()
 	eduCenterValidator := validators.NewEduCenterValidator()
+	courseValidator := validators.NewCourseValidator()
+	reviewValidator := validators.NewReviewValidator()
+	moderationValidator := validators.NewModerationValidator()
+	ratingResponseValidator := validators.NewRatingResponseValidator()
+	geocodingValidator := validators.NewGeocodingValidator()
+	searchValidator := validators.NewSearchValidator()
+	matchValidator := validators.NewMatchValidator()
+	recommendationValidator := validators.NewRecommendationValidator()
+	scheduleValidator := validators.NewScheduleValidator()
+	authValidator := validators.NewAuthValidator()
+
+	// INITIALIZE GEOCODER
+	geocoder, err := services.NewGazetteerGeocoder(config.GetEnv("GAZETTEER_PATH", "data/gazetteer.csv"))
+	if err != nil {
+		return &Application{}, fmt.Errorf("error on loading gazetteer: %v", err)
+	}
+
+	// INITIALIZE CALENDAR TIME ZONE
+	calendarLocation, err := time.LoadLocation(config.GetEnv("CALENDAR_TIMEZONE", "Asia/Tashkent"))
+	if err != nil {
+		return &Application{}, fmt.Errorf("invalid CALENDAR_TIMEZONE")
+	}
+
+	// INITIALIZE MAILER
+	mailSender, err := newMailer()
+	if err != nil {
+		return &Application{}, fmt.Errorf("error on initializing mailer: %v", err)
+	}
+	//responses don't depend on mail server, so they can't tell registered emails from unknown ones
+	mailSender = mailer.NewAsyncMailer(mailSender, mailWorkers, mailQueueSize, func(message mailer.Message, err error) {
+		logger.Error("Failed to send email", zap.String("subject", message.Subject), zap.Error(err))
+	})
+
+	requireVerifiedEmail, err := strconv.ParseBool(config.GetEnv("REQUIRE_VERIFIED_EMAIL", "false"))
+	if err != nil {
+		return &Application{}, fmt.Errorf("invalid REQUIRE_VERIFIED_EMAIL")
+	}
+	//verification links signed with the default secret could be forged by anyone
+	if requireVerifiedEmail && config.GetEnv("JWT_EMAIL_SECRET", "") == "" && config.GetEnv("JWT_SECRET", "") == "" {
+		return &Application{}, fmt.Errorf("JWT_EMAIL_SECRET or JWT_SECRET must be set when REQUIRE_VERIFIED_EMAIL is true")
+	}
 
 	// INITIALIZE SERVICES
-	userService := services.NewUserService(userRepository, userValidator)
-	authService := services.NewAuthService(userService)
-	eduCenterService := services.NewEduCenterService(eduCenterRepository, eduCenterValidator)
-	courseService := services.NewCourseService(courseRepasitory)
+	userService := services.NewUserService(userRepository, userValidator, mailSender,
+		config.GetEnv("EMAIL_VERIFICATION_URL", "http://localhost:8080/api/auth/verify-email"), logger)
+	authService := services.NewAuthService(userService, userRepository, tokenRepository, authValidator, mailSender,
+		config.GetEnv("PASSWORD_RESET_URL", "http://localhost:8080/reset-password"), requireVerifiedEmail)
+	courseService := services.NewCourseService(courseRepasitory, courseValidator, calendarLocation)
+	eduCenterService := services.NewEduCenterService(eduCenterRepository, eduCenterValidator, courseService, geocoder)
+	reviewService := services.NewReviewService(reviewRepository, reviewValidator)
+	moderationService := services.NewModerationService(moderationRepository, moderationValidator)
+	ratingResponseService := services.NewRatingResponseService(ratingResponseRepository, ratingResponseValidator)
+	geocodingService := services.NewGeocodingService(geocoder, geocodingValidator)
+	searchService := services.NewSearchService(searchRepository, searchValidator, geocoder)
+	matchService := services.NewMatchService(matchRepository, matchValidator)
+	recommendationService := services.NewRecommendationService(recommendationRepository, recommendationValidator, matchService)
+	scheduleService := services.NewScheduleService(scheduleRepository, scheduleValidator)
 
 	// INITIALIZE HANDLERS
 	userHandler := handlers.NewUserHandler(userService, logger)
 	eduCenterHandler := handlers.NewEduCenterHandler(eduCenterService, logger)
 	authHandler := handlers.NewAuthHandler(authService, logger)
 	courseHandler := handlers.NewCourseHandler(courseService, logger)
+	reviewHandler := handlers.NewReviewHandler(reviewService, logger)
+	moderationHandler := handlers.NewModerationHandler(moderationService, logger)
+	ratingResponseHandler := handlers.NewRatingResponseHandler(ratingResponseService, logger)
+	geocodingHandler := handlers.NewGeocodingHandler(geocodingService, logger)
+	searchHandler := handlers.NewSearchHandler(searchService, logger)
+	matchHandler := handlers.NewMatchHandler(matchService, logger)
+	recommendationHandler := handlers.NewRecommendationHandler(recommendationService, logger)
+	scheduleHandler := handlers.NewScheduleHandler(scheduleService, logger)
+
+	// INITIALIZE JOBS
+	similarityInterval, err := time.ParseDuration(config.GetEnv("SIMILARITY_REFRESH_INTERVAL", "1h"))
+	if err != nil || similarityInterval <= 0 {
+		return &Application{}, fmt.Errorf("invalid SIMILARITY_REFRESH_INTERVAL")
+	}
+	similarityJob := jobs.NewSimilarityJob(recommendationService, similarityInterval, logger)
 
 	//INITIALIZE Global Error Handler
 	globalErrorHandler := custom_errors.NewGlobalErrorHandler(logger)
@@ -66,12 +156,45 @@ func InitDependencies() (*Application, error) {
 	app := &Application{
 		GlobalErrorHandler: globalErrorHandler,
 		Handlers: Handlers{
-			AuthHandler:      authHandler,
-			EduCenterHandler: eduCenterHandler,
-			UserHandler:      userHandler,
-			CourseHandler:    courseHandler,
+			AuthHandler:           authHandler,
+			EduCenterHandler:      eduCenterHandler,
+			UserHandler:           userHandler,
+			CourseHandler:         courseHandler,
+			ReviewHandler:         reviewHandler,
+			ModerationHandler:     moderationHandler,
+			RatingResponseHandler: ratingResponseHandler,
+			GeocodingHandler:      geocodingHandler,
+			SearchHandler:         searchHandler,
+			MatchHandler:          matchHandler,
+			RecommendationHandler: recommendationHandler,
+			ScheduleHandler:       scheduleHandler,
 		},
+		Jobs:   []jobs.JobInterface{similarityJob},
 		Logger: logger,
 	}
 	return app, nil
 }
+
+// emails are sent by this many workers in background, at most mailQueueSize of them wait
+const (
+	mailWorkers   = 2
+	mailQueueSize = 100
+)
+
+// newMailer returns mailer chosen by MAILER, "file" one writes emails to MAIL_DIR or stdout for local development
+func newMailer() (mailer.Mailer, error) {
+	switch config.GetEnv("MAILER", "file") {
+	case "smtp":
+		return mailer.NewSMTPMailer(mailer.SMTPConfig{
+			Host:     config.GetEnv("SMTP_HOST", "localhost"),
+			Port:     config.GetEnv("SMTP_PORT", "587"),
+			Username: config.GetEnv("SMTP_USERNAME", ""),
+			Password: config.GetEnv("SMTP_PASSWORD", ""),
+			From:     config.GetEnv("MAIL_FROM", "no-reply@edumatch.uz"),
+		}), nil
+	case "file":
+		return mailer.NewFileMailer(config.GetEnv("MAIL_DIR", ""))
+	default:
+		return nil, fmt.Errorf("unknown MAILER, use smtp or file")
+	}
+}
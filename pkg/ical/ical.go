@@ -0,0 +1,239 @@
+package ical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the media type of iCalendar files
+const ContentType = "text/calendar; charset=utf-8"
+
+const (
+	dateTimeFormat    = "20060102T150405"
+	utcDateTimeFormat = "20060102T150405Z"
+	// content lines longer than this many octets are folded
+	maxLineOctets = 75
+	// offset changes of time zone are listed till this many years after the last event starts,
+	// so open ended weekly events keep their wall clock time
+	zoneYearsAhead = 10
+)
+
+// Calendar is the RFC 5545 calendar with events in one time zone
+type Calendar struct {
+	ProdID   string
+	Name     string
+	Location *time.Location
+	Events   []Event
+}
+
+// Event is the RFC 5545 event, Start and End are wall clock times in time zone of calendar.
+// Stamp is the time event data was last revised, RRule is the recurrence rule value without "RRULE:".
+type Event struct {
+	UID         string
+	Stamp       time.Time
+	Start       time.Time
+	End         time.Time
+	RRule       string
+	Summary     string
+	Location    string
+	Description string
+}
+
+// Encode returns calendar as iCalendar text
+func (c Calendar) Encode() []byte {
+	var b builder
+	b.line("BEGIN", "VCALENDAR")
+	b.line("VERSION", "2.0")
+	b.line("PRODID", c.ProdID)
+	b.line("CALSCALE", "GREGORIAN")
+	if c.Name != "" {
+		b.line("X-WR-CALNAME", escapeText(c.Name))
+	}
+	b.line("X-WR-TIMEZONE", c.Location.String())
+	from, to := c.zonePeriod()
+	b.timezone(c.Location, from, to)
+	for _, event := range c.Events {
+		b.event(event, c.Location)
+	}
+	b.line("END", "VCALENDAR")
+	return []byte(b.String())
+}
+
+// WeeklyRule returns RRULE value repeating event every week on its day till until, zero until repeats it forever.
+// RFC 5545 requires UNTIL in UTC when start of event has time zone.
+func WeeklyRule(day time.Weekday, until time.Time) string {
+	rule := "FREQ=WEEKLY;BYDAY=" + weekdays[day]
+	if !until.IsZero() {
+		rule += ";UNTIL=" + until.UTC().Format(utcDateTimeFormat)
+	}
+	return rule
+}
+
+var weekdays = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+type builder struct {
+	strings.Builder
+}
+
+// zonePeriod returns period which offsets of calendar time zone are described in, from the beginning of year
+// of the first event till zoneYearsAhead years after the last one starts or now
+func (c Calendar) zonePeriod() (time.Time, time.Time) {
+	first, last := time.Now(), time.Now()
+	for _, event := range c.Events {
+		if event.Start.Before(first) {
+			first = event.Start
+		}
+		if event.Start.After(last) {
+			last = event.Start
+		}
+	}
+	from := time.Date(first.In(c.Location).Year(), time.January, 1, 0, 0, 0, 0, c.Location)
+	return from, last.AddDate(zoneYearsAhead, 0, 0)
+}
+
+// observance is STANDARD or DAYLIGHT part of VTIMEZONE, it starts at Starts given in local time of OffsetFrom
+type observance struct {
+	daylight   bool
+	name       string
+	offsetFrom int
+	offsetTo   int
+	starts     []time.Time
+}
+
+// timezone writes VTIMEZONE of location with offset it has at from and all its changes till to.
+// Changes to the same offset and name are one observance with RDATE of each change.
+func (b *builder) timezone(location *time.Location, from, to time.Time) {
+	name, offset := from.In(location).Zone()
+	observances := []*observance{{
+		daylight:   from.In(location).IsDST(),
+		name:       name,
+		offsetFrom: offset,
+		offsetTo:   offset,
+		starts:     []time.Time{from},
+	}}
+	for _, change := range zoneChanges(location, from, to) {
+		name, offset := change.In(location).Zone()
+		_, previous := change.Add(-time.Second).In(location).Zone()
+		daylight := change.In(location).IsDST()
+		var current *observance
+		for _, o := range observances[1:] {
+			if o.daylight == daylight && o.name == name && o.offsetFrom == previous && o.offsetTo == offset {
+				current = o
+			}
+		}
+		if current == nil {
+			current = &observance{daylight: daylight, name: name, offsetFrom: previous, offsetTo: offset}
+			observances = append(observances, current)
+		}
+		current.starts = append(current.starts, change)
+	}
+
+	b.line("BEGIN", "VTIMEZONE")
+	b.line("TZID", location.String())
+	for _, o := range observances {
+		kind := "STANDARD"
+		if o.daylight {
+			kind = "DAYLIGHT"
+		}
+		starts := make([]string, len(o.starts))
+		for i, start := range o.starts {
+			starts[i] = start.UTC().Add(time.Duration(o.offsetFrom) * time.Second).Format(dateTimeFormat)
+		}
+		b.line("BEGIN", kind)
+		b.line("DTSTART", starts[0])
+		if len(starts) > 1 {
+			b.line("RDATE", strings.Join(starts[1:], ","))
+		}
+		b.line("TZOFFSETFROM", formatOffset(o.offsetFrom))
+		b.line("TZOFFSETTO", formatOffset(o.offsetTo))
+		b.line("TZNAME", escapeText(o.name))
+		b.line("END", kind)
+	}
+	b.line("END", "VTIMEZONE")
+}
+
+// zoneChanges returns instants offset, name or daylight saving of location changes at between from and to.
+// Zones change at most a few times a year, so days are scanned and changed ones are bisected to the second.
+func zoneChanges(location *time.Location, from, to time.Time) []time.Time {
+	var changes []time.Time
+	zone := func(t time.Time) (string, int, bool) {
+		t = t.In(location)
+		name, offset := t.Zone()
+		return name, offset, t.IsDST()
+	}
+	for start := from; start.Before(to); start = start.Add(24 * time.Hour) {
+		end := start.Add(24 * time.Hour)
+		startName, startOffset, startDST := zone(start)
+		endName, endOffset, endDST := zone(end)
+		if startName == endName && startOffset == endOffset && startDST == endDST {
+			continue
+		}
+		//the first second of new zone is in (low, high]
+		low, high := start, end
+		for high.Sub(low) > time.Second {
+			middle := low.Add(high.Sub(low) / 2).Truncate(time.Second)
+			name, offset, dst := zone(middle)
+			if name == startName && offset == startOffset && dst == startDST {
+				low = middle
+			} else {
+				high = middle
+			}
+		}
+		changes = append(changes, high)
+	}
+	return changes
+}
+
+func (b *builder) event(event Event, location *time.Location) {
+	tzid := ";TZID=" + location.String()
+	b.line("BEGIN", "VEVENT")
+	b.line("UID", event.UID)
+	b.line("DTSTAMP", event.Stamp.UTC().Format(utcDateTimeFormat))
+	b.line("DTSTART"+tzid, event.Start.Format(dateTimeFormat))
+	b.line("DTEND"+tzid, event.End.Format(dateTimeFormat))
+	if event.RRule != "" {
+		b.line("RRULE", event.RRule)
+	}
+	b.line("SUMMARY", escapeText(event.Summary))
+	if event.Location != "" {
+		b.line("LOCATION", escapeText(event.Location))
+	}
+	if event.Description != "" {
+		b.line("DESCRIPTION", escapeText(event.Description))
+	}
+	b.line("END", "VEVENT")
+}
+
+// line writes content line folded to lines of at most 75 octets without splitting utf-8 characters
+func (b *builder) line(name, value string) {
+	line := name + ":" + value
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		//continuation lines start with a space
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func escapeText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(text)
+}
+
+func formatOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+}
@@ -0,0 +1,151 @@
+package mailer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message is the plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers emails, implementations are picked by configuration
+type Mailer interface {
+	Send(message Message) error
+}
+
+// SMTPConfig is the server and sender address of SMTPMailer, empty Username sends mails without authentication
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPMailer sends emails through SMTP server
+type SMTPMailer struct {
+	config SMTPConfig
+}
+
+func NewSMTPMailer(config SMTPConfig) Mailer {
+	return &SMTPMailer{config: config}
+}
+
+func (m *SMTPMailer) Send(message Message) error {
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+	addr := net.JoinHostPort(m.config.Host, m.config.Port)
+	return smtp.SendMail(addr, auth, m.config.From, []string{message.To}, encode(m.config.From, message))
+}
+
+// FileMailer writes emails to files of directory instead of sending them, it is meant for local development.
+// Without directory emails are printed to stdout.
+type FileMailer struct {
+	dir string
+	out io.Writer
+	mu  sync.Mutex
+}
+
+func NewFileMailer(dir string) (Mailer, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	return &FileMailer{dir: dir, out: os.Stdout}, nil
+}
+
+func (m *FileMailer) Send(message Message) error {
+	data := encode("edumatch@localhost", message)
+	if m.dir == "" {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		_, err := m.out.Write(append(data, '\n'))
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405.000000000"), sanitizeFileName(message.To))
+	return os.WriteFile(filepath.Join(m.dir, name), data, 0o644)
+}
+
+// ErrQueueFull is reported for emails AsyncMailer had no room for
+var ErrQueueFull = errors.New("mail queue is full")
+
+// AsyncMailer queues emails and sends them by another mailer in background, so requests neither wait for
+// mail server nor fail with it. Failed and dropped emails are reported to onError, queued ones are lost on exit.
+type AsyncMailer struct {
+	mailer  Mailer
+	queue   chan Message
+	onError func(message Message, err error)
+}
+
+func NewAsyncMailer(mailer Mailer, workers, queueSize int, onError func(message Message, err error)) Mailer {
+	m := &AsyncMailer{
+		mailer:  mailer,
+		queue:   make(chan Message, queueSize),
+		onError: onError,
+	}
+	for i := 0; i < workers; i++ {
+		go m.work()
+	}
+	return m
+}
+
+// Send queues message and never fails
+func (m *AsyncMailer) Send(message Message) error {
+	select {
+	case m.queue <- message:
+	default:
+		m.onError(message, ErrQueueFull)
+	}
+	return nil
+}
+
+func (m *AsyncMailer) work() {
+	for message := range m.queue {
+		if err := m.mailer.Send(message); err != nil {
+			m.onError(message, err)
+		}
+	}
+}
+
+// encode returns message with headers, line breaks are removed from header values so they can't add headers
+func encode(from string, message Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + headerValue(from) + "\r\n")
+	b.WriteString("To: " + headerValue(message.To) + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", headerValue(message.Subject)) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(message.Body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+func headerValue(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
+
+func sanitizeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r < ' ' {
+			return '_'
+		}
+		return r
+	}, name)
+}